@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	auditLogCmd.Flags().String("group", "", "the group of the audit events")
+	auditLogCmd.Flags().String("since", "", "only events created after, RFC3339 or relative like 7d")
+	auditLogCmd.Flags().String("until", "", "only events created before, RFC3339 or relative like 1w")
+	auditLogCmd.Flags().String("author", "", "only events of the author username")
+	rootCmd.AddCommand(auditLogCmd)
+}
+
+var auditLogCmd = &cobra.Command{
+	Use:         "audit-log --group <group> [--since <time>] [--until <time>] [--author <username>]",
+	Short:       "Show the audit events of the group, the latest first",
+	Args:        cobra.NoArgs,
+	Run:         auditLog,
+	Annotations: structuredOutput,
+}
+
+func auditLog(cmd *cobra.Command, _ []string) {
+	group, _ := cmd.Flags().GetString("group")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	author, _ := cmd.Flags().GetString("author")
+	if group == "" {
+		utils.Err("group is required, use --group")
+	}
+	opts := &gitlab.ListAuditEventsOptions{}
+	if since != "" {
+		t, err := utils.ParseRelativeTime(since)
+		utils.Check(err)
+		opts.CreatedAfter = gitlab.Ptr(t)
+	}
+	if until != "" {
+		t, err := utils.ParseRelativeTime(until)
+		utils.Check(err)
+		opts.CreatedBefore = gitlab.Ptr(t)
+	}
+	internal.Setup(profile)
+	client := internal.NewClient()
+	// the group audit events api can't filter by author
+	authorID := 0
+	if author != "" {
+		ids, err := internal.UserIDs(client, []string{author}, false)
+		utils.Check(err)
+		authorID = ids[0]
+	}
+
+	events := []*gitlab.AuditEvent{}
+	for _, e := range internal.ListAuditEvents(client, group, opts) {
+		if authorID == 0 || e.AuthorID == authorID {
+			events = append(events, e)
+		}
+	}
+	// newest first, the events without a time last
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].CreatedAt, events[j].CreatedAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+	printResult(events, func() {
+		if len(events) == 0 {
+			utils.Err("no audit events found")
+		}
+		table := utils.NewTable()
+		fmt.Fprintln(table, "TIME\tAUTHOR\tACTION\tTARGET\tIP")
+		for _, e := range events {
+			d := e.Details
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", formatTime(e.CreatedAt), orDash(d.AuthorName), auditAction(e), orDash(d.TargetDetails), orDash(d.IPAddress))
+		}
+		_ = table.Flush()
+	})
+}
+
+// auditAction describe the change of the audit event, the details fields depend on the action
+func auditAction(e *gitlab.AuditEvent) string {
+	d := e.Details
+	switch {
+	case d.CustomMessage != "":
+		return d.CustomMessage
+	case d.Add != "":
+		return fmt.Sprintf("add %s %s", d.Add, d.As)
+	case d.Remove != "":
+		return "remove " + d.Remove
+	case d.Change != "":
+		return fmt.Sprintf("change %s from %s to %s", d.Change, orDash(d.From), orDash(d.To))
+	case d.FailedLogin != "":
+		return "failed login " + d.FailedLogin
+	}
+	return orDash(e.EventName)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	blameCmd.Flags().String("ref", "", "branch, tag or commit, default the current branch, or the default branch of --project")
+	blameCmd.Flags().String("project", "", "project path with namespace, default the current repo")
+	rootCmd.AddCommand(blameCmd)
+}
+
+var blameCmd = &cobra.Command{
+	Use:         "blame <file> [--ref <ref>]",
+	Short:       "Show the blame of the file with the merge request of each commit",
+	Args:        cobra.ExactArgs(1),
+	Run:         blame,
+	Annotations: structuredOutput,
+}
+
+func blame(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project, file := projectFile(cmd, args[0])
+	client := internal.NewClient()
+	ref := refFlag(cmd, client, project)
+	ranges, err := internal.BlameFile(client, project, ref, file)
+	utils.Check(err)
+
+	mrs := map[string]*gitlab.BasicMergeRequest{}
+	authorWidth := 0
+	for _, r := range ranges {
+		authorWidth = max(authorWidth, len(r.Commit.AuthorName))
+		if _, ok := mrs[r.Commit.ID]; !ok {
+			mr, err := internal.CommitMergeRequest(client, project, r.Commit.ID)
+			utils.Check(err)
+			mrs[r.Commit.ID] = mr
+		}
+	}
+
+	result := make([]blameRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, blameRange{r, mrs[r.Commit.ID]})
+	}
+	printResult(result, func() {
+		w, wait := utils.StartPager()
+		defer wait()
+		lineNo := 0
+		var referenced []*gitlab.BasicMergeRequest
+		seen := map[int]bool{}
+		for k, r := range ranges {
+			mr := mrs[r.Commit.ID]
+			mrRef := ""
+			if mr != nil {
+				mrRef = fmt.Sprintf("!%d", mr.IID)
+				if !seen[mr.IID] {
+					seen[mr.IID] = true
+					referenced = append(referenced, mr)
+				}
+			}
+			date := "-"
+			if r.Commit.AuthoredDate != nil {
+				date = r.Commit.AuthoredDate.Local().Format("2006-01-02")
+			}
+			info := fmt.Sprintf("%.8s %-*s %s %-6s", r.Commit.ID, authorWidth, r.Commit.AuthorName, date, mrRef)
+			for i, line := range r.Lines {
+				lineNo++
+				if i > 0 {
+					info = strings.Repeat(" ", len(info))
+				}
+				fmt.Fprintf(w, "%s %5d │ %s\n", utils.IndexColor(info, k), lineNo, line)
+			}
+		}
+
+		if len(referenced) > 0 {
+			fmt.Fprintln(w)
+		}
+		for _, mr := range referenced {
+			fmt.Fprintf(w, "!%d %s %s\n", mr.IID, mr.Title, mr.WebURL)
+		}
+	})
+}
+
+// blameRange is the json and yaml output of lab blame
+type blameRange struct {
+	*gitlab.FileBlameRange
+	MergeRequest *gitlab.BasicMergeRequest `json:"merge_request"`
+}
+
+// projectFile return the --project flag and the path of the file in the repo. Without
+// --project, it is the current repo and the file is relative to the current directory
+func projectFile(cmd *cobra.Command, file string) (string, string) {
+	file = filepath.ToSlash(file)
+	if project, _ := cmd.Flags().GetString("project"); project != "" {
+		return project, file
+	}
+	prefix, err := internal.GitCommand("rev-parse", "--show-prefix")
+	if err == nil {
+		file = path.Join(strings.TrimSpace(prefix), file)
+	}
+	return internal.CurrentProject(), file
+}
+
+// refFlag return the --ref flag, default the current branch in the current repo,
+// or the default branch of the --project
+func refFlag(cmd *cobra.Command, client *gitlab.Client, project string) string {
+	if ref, _ := cmd.Flags().GetString("ref"); ref != "" {
+		return ref
+	}
+	if p, _ := cmd.Flags().GetString("project"); p == "" {
+		return internal.CurrentBranch()
+	}
+	branch, err := internal.DefaultBranch(client, project)
+	utils.Check(err)
+	return branch
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	branchCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	branchListCmd.Flags().String("search", "", "only list the branches containing the search, case-insensitive")
+	branchListCmd.Flags().Bool("merged", false, "only list the merged branches")
+	branchDeleteCmd.Flags().Bool("merged", false, "delete all merged branches, except the default and protected ones")
+	branchDeleteCmd.Flags().BoolP("yes", "y", false, "delete multiple branches without confirmation")
+	branchCmd.AddCommand(branchListCmd)
+	branchCmd.AddCommand(branchDeleteCmd)
+	rootCmd.AddCommand(branchCmd)
+}
+
+var branchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "Manage the remote branches of the project",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var branchListCmd = &cobra.Command{
+	Use:         "list [--search <q>] [--merged]",
+	Short:       "List the branches with their last commit",
+	Run:         listBranches,
+	Annotations: structuredOutput,
+}
+
+var branchDeleteCmd = &cobra.Command{
+	Use:   "delete <branch>... | --merged [--yes]",
+	Short: "Delete the remote branches",
+	Run:   deleteBranches,
+}
+
+func listBranches(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	search, _ := cmd.Flags().GetString("search")
+	merged, _ := cmd.Flags().GetBool("merged")
+	branches := filterBranches(internal.ListBranches(internal.NewClient(), project), search, merged)
+	if len(branches) == 0 {
+		utils.Err("no branches found")
+	}
+
+	printResult(branches, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "BRANCH\tCOMMIT\tAUTHOR\tDATE\tPROTECTED")
+		for _, b := range branches {
+			sha, author, date := "-", "-", "-"
+			if b.Commit != nil {
+				sha, author, date = b.Commit.ShortID, b.Commit.AuthorName, formatTime(b.Commit.CommittedDate)
+			}
+			name := b.Name
+			if b.Default {
+				name += " (default)"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%t\n", name, sha, author, date, b.Protected)
+		}
+		_ = table.Flush()
+	})
+}
+
+// filterBranches return the branches containing the search, and only the merged ones if merged
+func filterBranches(branches []*gitlab.Branch, search string, merged bool) []*gitlab.Branch {
+	search = strings.ToLower(search)
+	result := make([]*gitlab.Branch, 0, len(branches))
+	for _, b := range branches {
+		if merged && !b.Merged {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(b.Name), search) {
+			continue
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+func deleteBranches(cmd *cobra.Command, args []string) {
+	merged, _ := cmd.Flags().GetBool("merged")
+	if merged == (len(args) > 0) {
+		utils.Err("use either <branch> or --merged")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+
+	branches := args
+	if merged {
+		branches = nil
+		for _, b := range filterBranches(internal.ListBranches(client, project), "", true) {
+			if b.Default || b.Protected {
+				continue
+			}
+			branches = append(branches, b.Name)
+		}
+		if len(branches) == 0 {
+			fmt.Println("No merged branches to delete")
+			return
+		}
+	}
+	yes, _ := cmd.Flags().GetBool("yes")
+	if len(branches) > 1 && !yes {
+		fmt.Println(strings.Join(branches, "\n"))
+		if !utils.Confirm(fmt.Sprintf("Delete the %d branches of %s?", len(branches), project)) {
+			return
+		}
+	}
+
+	failed := 0
+	for _, branch := range branches {
+		if err := internal.DeleteBranch(client, project, branch); err != nil {
+			utils.Warn("delete ", branch, " failed: ", err)
+			failed++
+			continue
+		}
+		fmt.Println("Branch", branch, "deleted")
+	}
+	if failed > 0 {
+		utils.Err(failed, "branches not deleted")
+	}
+}
@@ -22,7 +22,7 @@ var browserCmd = &cobra.Command{
 }
 
 func openURL(cmd *cobra.Command, args []string) {
-	internal.Setup()
+	internal.Setup(profile)
 	var project string
 	if len(args) > 0 {
 		project = args[0]
@@ -30,7 +30,7 @@ func openURL(cmd *cobra.Command, args []string) {
 		project = ""
 	}
 	if project == "" {
-		project = internal.FuzzyLine(internal.ProjectPath)
+		project = internal.FuzzyFinder(internal.SyncedProjects(internal.ProjectFilter{}))
 	}
 	// ctrl-c
 	if project == "" {
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ciCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	rootCmd.AddCommand(ciCmd)
+}
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Check and show the ci config of the project",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	ciGraphCmd.Flags().Int("pipeline", 0, "pipeline id, default the latest pipeline of the current branch")
+	ciCmd.AddCommand(ciGraphCmd)
+}
+
+var ciGraphCmd = &cobra.Command{
+	Use:   "graph [--pipeline <id>]",
+	Short: "Draw the stages and jobs of the pipeline",
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		project := projectFlag(cmd)
+		client := internal.NewClient()
+		pipelineID := pipelineFlag(cmd, client, project)
+		jobs := pipelineJobs(client, project, pipelineID)
+		if len(jobs) == 0 {
+			utils.Err("no jobs in the pipeline")
+		}
+		fmt.Printf("Pipeline #%d\n", pipelineID)
+		fmt.Print(pipelineGraph(jobs))
+	},
+}
+
+// pipelineGraph draw a column per stage with its jobs, the stages are in the order of their first job
+func pipelineGraph(jobs []*gitlab.Job) string {
+	var stages []string
+	stageJobs := map[string][]*gitlab.Job{}
+	for _, job := range jobs {
+		if _, ok := stageJobs[job.Stage]; !ok {
+			stages = append(stages, job.Stage)
+		}
+		stageJobs[job.Stage] = append(stageJobs[job.Stage], job)
+	}
+
+	// the width of a cell is the icon, a space and the longest job or stage name
+	widths := make([]int, len(stages))
+	rows := 0
+	for i, stage := range stages {
+		widths[i] = utf8.RuneCountInString(stage)
+		for _, job := range stageJobs[stage] {
+			widths[i] = max(widths[i], utf8.RuneCountInString(job.Name)+2)
+		}
+		rows = max(rows, len(stageJobs[stage]))
+	}
+
+	var b strings.Builder
+	border := func(left, middle, right string) {
+		b.WriteString(left)
+		for i, w := range widths {
+			if i > 0 {
+				b.WriteString(middle)
+			}
+			b.WriteString(strings.Repeat("─", w+2))
+		}
+		b.WriteString(right + "\n")
+	}
+	row := func(cells []string, plain []string) {
+		for i, w := range widths {
+			fmt.Fprintf(&b, "│ %s%s ", cells[i], strings.Repeat(" ", w-utf8.RuneCountInString(plain[i])))
+		}
+		b.WriteString("│\n")
+	}
+
+	border("┌", "┬", "┐")
+	row(stages, stages)
+	border("├", "┼", "┤")
+	for r := 0; r < rows; r++ {
+		cells := make([]string, len(stages))
+		plain := make([]string, len(stages))
+		for i, stage := range stages {
+			if r < len(stageJobs[stage]) {
+				job := stageJobs[stage][r]
+				cells[i] = utils.StatusIcon(job.Status) + " " + job.Name
+				plain[i] = "x " + job.Name
+			}
+		}
+		row(cells, plain)
+	}
+	border("└", "┴", "┘")
+	return b.String()
+}
@@ -6,25 +6,35 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	gitlab "gitlab.com/gitlab-org/api/client-go"
 
 	"github.com/ackerr/lab/internal"
 	"github.com/ackerr/lab/utils"
 )
 
 func init() {
+	ciLintCmd.Flags().StringP("file", "f", ".gitlab-ci.yml", "the ci config file")
+	ciCmd.AddCommand(ciLintCmd)
 	rootCmd.AddCommand(lintCmd)
 }
 
 var lintCmd = &cobra.Command{
-	Use:   "lint",
-	Short: "Check .gitlab-ci.yml syntax",
+	Use:   "lint [<path>]",
+	Short: "Check .gitlab-ci.yml syntax, same as lab ci lint",
+	Args:  cobra.MaximumNArgs(1),
 	Run:   lint,
 }
 
-// asdfasdf
-func lint(_ *cobra.Command, args []string) {
-	internal.Setup()
+var ciLintCmd = &cobra.Command{
+	Use:   "lint [--file <path>]",
+	Short: "Check the ci config with the project namespace, exit 1 if invalid",
+	Run: func(cmd *cobra.Command, _ []string) {
+		path, _ := cmd.Flags().GetString("file")
+		lintCIConfig(cmd, path)
+	},
+}
+
+// lint the .gitlab-ci.yml in the dir of the arg, default the current dir
+func lint(cmd *cobra.Command, args []string) {
 	var path string
 	var err error
 	if len(args) > 0 {
@@ -37,23 +47,30 @@ func lint(_ *cobra.Command, args []string) {
 	if !strings.HasSuffix(path, ".gitlab-ci.yml") {
 		path = filepath.Join(path, ".gitlab-ci.yml")
 	}
+	lintCIConfig(cmd, path)
+}
+
+// lintCIConfig print the errors and warnings of the ci config, exit 1 if invalid
+func lintCIConfig(cmd *cobra.Command, path string) {
 	if !utils.FileExists(path) {
 		utils.Err(path, "not exist")
 	}
 	content, err := os.ReadFile(path)
 	utils.Check(err)
 	if len(content) == 0 {
-		utils.Err("empty .gitlab-ci.yml")
+		utils.Err("empty", path)
 	}
-	client := internal.NewClient()
-	// result, _, err := client.Validate.Lint(string(content))
-	result, _, err := client.Validate.Lint(&gitlab.LintOptions{
-		Content: string(content),
-	})
+	internal.Setup(profile)
+	result, err := internal.LintCIConfig(internal.NewClient(), projectFlag(cmd), string(content))
 	utils.Check(err)
-	if result.Status != "valid" {
-		for _, e := range result.Errors {
-			utils.PrintlnWithColor(utils.ColorBg("ERROR", "#F08080"), e)
-		}
+	for _, w := range result.Warnings {
+		utils.PrintlnWithColor(utils.ColorBg("WARNING", "#F0C080"), w)
+	}
+	for _, e := range result.Errors {
+		utils.PrintlnWithColor(utils.ColorBg("ERROR", "#F08080"), e)
+	}
+	if !result.Valid {
+		os.Exit(1)
 	}
+	utils.PrintlnWithColor(utils.ColorFg(path+" is valid", internal.MainConfig.ThemeColor))
 }
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -16,6 +20,11 @@ import (
 func init() {
 	cloneCmd.Flags().Bool("https", false, "clone with https, default use ssh")
 	cloneCmd.Flags().BoolP("current", "c", false, "clone repo to current directory")
+	cloneCmd.Flags().Int("depth", 0, "create a shallow clone with the history truncated to n commits")
+	cloneCmd.Flags().StringP("branch", "b", "", "checkout the branch instead of the default branch")
+	cloneCmd.Flags().Bool("all", false, "clone all projects of the --group and its subgroups into the codespace")
+	cloneCmd.Flags().String("group", "", "the group path of --all")
+	cloneCmd.Flags().Bool("dry-run", false, "print the projects --all would clone")
 	cloneCmd.AddCommand(wikiCmd)
 	rootCmd.AddCommand(cloneCmd)
 }
@@ -27,49 +36,152 @@ var wikiCmd = &cobra.Command{
 }
 
 var cloneCmd = &cobra.Command{
-	Use:   "clone",
-	Short: "Clone the gitlab projects, like git clone",
+	Use:   "clone [<namespace/project>...] [--all --group <path> [--dry-run]]",
+	Short: "Clone the gitlab projects, like git clone, default fuzzy find the projects",
 	Run:   cloneRepo,
 }
 
-func cloneRepo(cmd *cobra.Command, _ []string) {
-	internal.Setup()
-	projects := internal.FuzzyLines(internal.ProjectPath)
+func cloneRepo(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		cloneGroup(cmd)
+		return
+	}
+	projects := args
+	if len(projects) == 0 {
+		projects = internal.FuzzyMultiFinder(internal.SyncedProjects(internal.ProjectFilter{}))
+	}
 	if len(projects) == 0 {
 		return
 	}
 
 	isHTTPS, _ := cmd.Flags().GetBool("https")
 	isCurrent, _ := cmd.Flags().GetBool("current")
+	opts := cloneOptions(cmd)
 	for _, project := range projects {
 		p := project
 		if cmd.Use == "wiki" {
 			p = p + ".wiki"
 		}
 		utils.PrintlnWithColor(utils.ColorFg("Cloning "+p, internal.MainConfig.ThemeColor))
-		clone(p, isHTTPS, isCurrent)
+		clone(p, isHTTPS, isCurrent, opts...)
+	}
+}
+
+// cloneGroup clone all projects of the group tree in parallel, the cloned ones are skipped
+func cloneGroup(cmd *cobra.Command) {
+	group, _ := cmd.Flags().GetString("group")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	isHTTPS, _ := cmd.Flags().GetBool("https")
+	if group == "" {
+		utils.Err("--all requires --group")
+	}
+	if internal.Config.Codespace == "" {
+		utils.Err("use <lab config> to set codespace first")
+	}
+	// git output of parallel clones is unreadable
+	opts := append(cloneOptions(cmd), "--quiet")
+
+	var projects []string
+	skipped := 0
+	for _, project := range internal.GroupProjects(cmd.Context(), group) {
+		if utils.FileExists(filepath.Join(codespacePath(project), ".git")) {
+			skipped++
+			continue
+		}
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	if dryRun {
+		for _, project := range projects {
+			fmt.Println(project, "->", codespacePath(project))
+		}
+		fmt.Printf("%d to clone, %d already cloned\n", len(projects), skipped)
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var failed, clonedProjects []string
+	sem := make(chan struct{}, max(internal.MainConfig.NumWorkers, 1))
+	for _, project := range projects {
+		if cmd.Context().Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(project string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			path := codespacePath(project)
+			err := os.MkdirAll(filepath.Dir(path), utils.DirPerm)
+			if err == nil {
+				err = internal.Clone(cloneURL(project, isHTTPS), path, opts...)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, project)
+				return
+			}
+			clonedProjects = append(clonedProjects, project)
+			fmt.Println("Cloned", project)
+		}(project)
 	}
+	wg.Wait()
+	markCloned(clonedProjects...)
+
+	fmt.Printf("%d cloned, %d skipped, %d failed\n", len(clonedProjects), skipped, len(failed))
+	for _, project := range failed {
+		utils.Warn("failed: ", project)
+	}
+}
+
+// cloneOptions return the git clone options of the flags
+func cloneOptions(cmd *cobra.Command) []string {
+	var opts []string
+	if branch, _ := cmd.Flags().GetString("branch"); branch != "" {
+		opts = append(opts, "--branch", branch)
+	}
+	if depth, _ := cmd.Flags().GetInt("depth"); depth > 0 {
+		opts = append(opts, "--depth", strconv.Itoa(depth))
+	}
+	return opts
 }
 
-func clone(project string, isHTTPS, isCurrent bool) {
-	var gitURL, path string
+// codespaceHost return the host of the gitlab, the projects are cloned into codespace/<host>/<project>
+func codespaceHost() string {
 	baseURL := internal.Config.BaseURL
 	if strings.HasPrefix(baseURL, "http") {
 		baseURL = strings.Split(baseURL, "://")[1]
 	}
+	return baseURL
+}
 
-	if !isHTTPS {
-		gitURL = strings.Join([]string{"git@", baseURL, ":", project, ".git"}, "")
-	} else {
-		gitURL = strings.Join([]string{internal.Config.BaseURL, project}, "/")
+// cloneURL return the ssh or https url of the project
+func cloneURL(project string, isHTTPS bool) string {
+	if isHTTPS {
+		return strings.Join([]string{internal.Config.BaseURL, project}, "/")
 	}
+	return strings.Join([]string{"git@", codespaceHost(), ":", project, ".git"}, "")
+}
+
+// codespacePath return the path of the project in the codespace
+func codespacePath(project string) string {
+	dirs := []string{internal.Config.Codespace, codespaceHost()}
+	dirs = append(dirs, strings.Split(project, "/")...)
+	return filepath.Join(dirs...)
+}
+
+// clone the project into the codespace, or the current directory, opts are the extra git clone options
+func clone(project string, isHTTPS, isCurrent bool, opts ...string) {
+	var path string
+	gitURL := cloneURL(project, isHTTPS)
 	codespace := internal.Config.Codespace
 	sign := make(chan os.Signal, 1)
 	signal.Notify(sign, syscall.SIGINT, syscall.SIGTERM)
 	if !isCurrent && len(codespace) > 0 {
-		dirs := []string{codespace, baseURL}
-		dirs = append(dirs, strings.Split(project, "/")...)
-		path = filepath.Join(dirs...)
+		path = codespacePath(project)
 		if utils.FileExists(path) {
 			_ = internal.Fetch(path)
 			return
@@ -86,5 +198,19 @@ func clone(project string, isHTTPS, isCurrent bool) {
 		dir := strings.Split(project, "/")
 		path = strings.Join([]string{path, dir[len(dir)-1]}, "/")
 	}
-	_ = internal.Clone(gitURL, path)
+	if err := internal.Clone(gitURL, path, opts...); err == nil {
+		markCloned(project)
+	}
+}
+
+// markCloned save the clone time of the synced project, it is only a hint, so errors are ignored
+func markCloned(projects ...string) {
+	db, err := internal.OpenProjectDB()
+	if err != nil {
+		return
+	}
+	defer db.Close()
+	for _, project := range projects {
+		_ = internal.MarkCloned(db, project)
+	}
 }
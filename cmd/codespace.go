@@ -20,33 +20,97 @@ var (
 func init() {
 	rootCmd.AddCommand(csCmd)
 	csCmd.Flags().IntVarP(&maxDepth, "depth", "d", defaultDepth, "maximum depth to filepath walk")
+	csCmd.Flags().Bool("clone", false, "clone the selected project if it is not in the codespace yet")
+	csCmd.Flags().Bool("https", false, "clone with https, default use ssh")
 }
 
+// notCloned mark the synced projects not in the codespace
+const notCloned = " (not cloned)"
+
 var csCmd = &cobra.Command{
-	Use:     "cs",
+	Use:     "cs [--clone]",
 	Aliases: []string{"ws"},
 	Short:   "Search repo in your codespace",
 	Run:     searchCodespace,
 }
 
-func searchCodespace(_ *cobra.Command, _ []string) {
-	internal.Setup()
+func searchCodespace(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
 	codespace := internal.Config.Codespace
 	if codespace == "" {
 		utils.Err("use <lab config> to set codespace first")
 	}
-	baseDepth := strings.Count(codespace, string(os.PathSeparator))
+	projects, err := codespaceRepos(codespace, maxDepth)
+	utils.Check(err)
+	projects = append(projects, syncedProjects(projects)...)
+	if len(projects) == 0 {
+		utils.Err("no projects in codespace")
+	}
+	path := internal.FuzzyFinder(projects)
+	if path == "" {
+		// cancel fzf, return current path
+		fmt.Println(os.Getenv("PWD"))
+		return
+	}
+	if !strings.HasSuffix(path, notCloned) {
+		fmt.Println(filepath.Join(codespace, path))
+		return
+	}
+
+	// the stdout is the path to cd, so the messages go to stderr
+	project := strings.TrimPrefix(strings.TrimSuffix(path, notCloned), codespaceHost()+"/")
+	if isClone, _ := cmd.Flags().GetBool("clone"); !isClone {
+		fmt.Fprintf(os.Stderr, "%s is not cloned yet, run `lab clone %s` or `lab cs --clone`\n", project, project)
+		fmt.Println(os.Getenv("PWD"))
+		return
+	}
+	isHTTPS, _ := cmd.Flags().GetBool("https")
+	clone(project, isHTTPS, false)
+	fmt.Println(filepath.Join(codespace, codespaceHost(), project))
+}
+
+// syncedProjects return the synced projects of lab sync which are not in the codespace,
+// in the same form as the cloned ones, <host>/<project>
+func syncedProjects(cloned []string) []string {
+	db, err := internal.OpenProjectDB()
+	if err != nil {
+		return nil
+	}
+	defer db.Close()
+	if internal.Config.ProjectsURL != "" {
+		// the stdout is the path to cd
+		if err = internal.RefreshURLProjects(db); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	exists := make(map[string]bool, len(cloned))
+	for _, p := range cloned {
+		exists[p] = true
+	}
+	var projects []string
+	for _, project := range internal.QueryProjects(db, internal.ProjectFilter{}) {
+		p := filepath.Join(codespaceHost(), project)
+		if !exists[p] {
+			projects = append(projects, p+notCloned)
+		}
+	}
+	return projects
+}
+
+// codespaceRepos return the git repos under the dir, relative to the dir, at most depth levels deep
+func codespaceRepos(dir string, depth int) ([]string, error) {
+	baseDepth := strings.Count(dir, string(os.PathSeparator))
 	var projects []string
-	err := filepath.Walk(codespace, func(path string, fileInfo os.FileInfo, err error) error {
+	err := filepath.Walk(dir, func(path string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return filepath.SkipDir
 		}
 		currentDepth := strings.Count(path, string(os.PathSeparator)) - baseDepth
-		if currentDepth > maxDepth {
+		if currentDepth > depth {
 			return filepath.SkipDir
 		}
 		if utils.FileExists(filepath.Join(path, ".git")) {
-			p := strings.Replace(path, codespace, "", 1)
+			p := strings.Replace(path, dir, "", 1)
 			if len(p) > 0 {
 				projects = append(projects, p[1:])
 			}
@@ -54,15 +118,5 @@ func searchCodespace(_ *cobra.Command, _ []string) {
 		}
 		return err
 	})
-	utils.Check(err)
-	if len(projects) == 0 {
-		utils.Err("no projects in codespace")
-	}
-	path := internal.FuzzyFinder(projects)
-	if path == "" {
-		// cancel fzf, return current path
-		fmt.Println(os.Getenv("PWD"))
-		return
-	}
-	fmt.Println(filepath.Join(codespace, path))
+	return projects, err
 }
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	commitCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	commitListCmd.Flags().String("ref", "", "branch or tag, default the project default branch")
+	commitListCmd.Flags().String("since", "", "only commits after, RFC3339 or relative like 7d")
+	commitListCmd.Flags().String("until", "", "only commits before, RFC3339 or relative like 1w")
+	commitListCmd.Flags().String("path", "", "only commits changing the file path")
+	commitListCmd.Flags().Bool("patch", false, "print the patch of the selected commit instead of open it in browser")
+	commitListCmd.Flags().Bool("print", false, "print the url instead of open it in browser")
+	commitShowCmd.Flags().Bool("stat", false, "only print the changed files with the number of added and removed lines")
+	commitCmd.AddCommand(commitListCmd)
+	commitCmd.AddCommand(commitShowCmd)
+	rootCmd.AddCommand(commitCmd)
+}
+
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Browse the project commits",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var commitListCmd = &cobra.Command{
+	Use:         "list [--ref <ref>] [--since <time>] [--until <time>] [--path <file>] [--patch]",
+	Short:       "Fuzzy find a commit and open its diff in browser",
+	Run:         listCommits,
+	Annotations: structuredOutput,
+}
+
+var commitShowCmd = &cobra.Command{
+	Use:   "show <sha> [--stat]",
+	Short: "Show the message and the diff of the commit in $PAGER",
+	Args:  cobra.ExactArgs(1),
+	Run:   showCommit,
+}
+
+func listCommits(cmd *cobra.Command, _ []string) {
+	opts := &gitlab.ListCommitsOptions{}
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		t, err := utils.ParseRelativeTime(since)
+		utils.Check(err)
+		opts.Since = gitlab.Ptr(t)
+	}
+	if until, _ := cmd.Flags().GetString("until"); until != "" {
+		t, err := utils.ParseRelativeTime(until)
+		utils.Check(err)
+		opts.Until = gitlab.Ptr(t)
+	}
+	if path, _ := cmd.Flags().GetString("path"); path != "" {
+		opts.Path = gitlab.Ptr(path)
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	ref, _ := cmd.Flags().GetString("ref")
+	client := internal.NewClient()
+	commits := internal.ListCommits(client, project, ref, opts)
+	if len(commits) == 0 {
+		utils.Err("no commits found")
+	}
+
+	// the table output is the finder, json and yaml print the commits
+	printResult(commits, func() {
+		lines := make([]string, 0, len(commits))
+		for _, c := range commits {
+			lines = append(lines, fmt.Sprintf("%s %s %s", c.ShortID, c.Title, utils.ColorFg(c.AuthorName, internal.MainConfig.ThemeColor)))
+		}
+		index := internal.FuzzyPreviewFinder(lines, func(i int) string {
+			return commitHeader(commits[i])
+		})
+		if index < 0 {
+			return
+		}
+		commit := commits[index]
+		if patch, _ := cmd.Flags().GetBool("patch"); patch {
+			diffs, err := internal.GetCommitDiff(client, project, commit.ID)
+			utils.Check(err)
+			pageDiff(commitPatch(diffs))
+			return
+		}
+		openOrPrint(cmd, commit.WebURL)
+	})
+}
+
+func showCommit(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	commit, err := internal.GetCommit(client, project, args[0])
+	utils.Check(err)
+	diffs, err := internal.GetCommitDiff(client, project, commit.ID)
+	utils.Check(err)
+
+	if stat, _ := cmd.Flags().GetBool("stat"); stat {
+		fmt.Print(commitHeader(commit))
+		printDiffStat(commitDiffs(diffs))
+		return
+	}
+	pageDiff(commitHeader(commit) + commitPatch(diffs))
+}
+
+// commitHeader return the commit id, author, date and the indented message, like git show
+func commitHeader(c *gitlab.Commit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "commit %s\nAuthor: %s <%s>\nDate:   %s\n\n", c.ID, c.AuthorName, c.AuthorEmail, formatTime(c.AuthoredDate))
+	for _, line := range strings.Split(strings.TrimSpace(c.Message), "\n") {
+		fmt.Fprintf(&b, "    %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// commitPatch return the diffs of the commit with the git diff headers
+func commitPatch(diffs []*gitlab.Diff) string {
+	var b strings.Builder
+	for _, d := range commitDiffs(diffs) {
+		b.WriteString(gitDiff(d))
+	}
+	return b.String()
+}
+
+// commitDiffs convert the commit diffs to merge request diffs, they have the same fields
+// in another order
+func commitDiffs(diffs []*gitlab.Diff) []*gitlab.MergeRequestDiff {
+	result := make([]*gitlab.MergeRequestDiff, 0, len(diffs))
+	for _, d := range diffs {
+		result = append(result, &gitlab.MergeRequestDiff{
+			OldPath:     d.OldPath,
+			NewPath:     d.NewPath,
+			AMode:       d.AMode,
+			BMode:       d.BMode,
+			Diff:        d.Diff,
+			NewFile:     d.NewFile,
+			RenamedFile: d.RenamedFile,
+			DeletedFile: d.DeletedFile,
+		})
+	}
+	return result
+}
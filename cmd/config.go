@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"os"
-	"os/exec"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -11,20 +12,94 @@ import (
 )
 
 func init() {
+	configCmd.Flags().BoolP("editor", "e", false, "use $EDITOR open config file")
+	configCmd.AddCommand(configValidateCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
 var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Use $EDITOR open config file",
+	Use:   "config [-e]",
+	Short: "Edit the config in a form, or use $EDITOR open config file",
 	Run:   editConfig,
 }
 
-func editConfig(_ *cobra.Command, _ []string) {
-	configPath := internal.ConfigPath
-	editor := utils.GetEnv("EDITOR", "vim")
-	command := exec.Command(editor, configPath)
-	command.Stdin = os.Stdin
-	command.Stdout = os.Stdout
-	_ = command.Run()
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the gitlab base url and token",
+	Run: func(_ *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		utils.Check(internal.ValidateConfig(internal.Config))
+		fmt.Println("Config is valid.")
+	},
+}
+
+// configField a field of the config form, the key in the section of the config file
+type configField struct {
+	section string
+	key     string
+	secret  bool
+}
+
+func editConfig(cmd *cobra.Command, _ []string) {
+	editor, _ := cmd.Flags().GetBool("editor")
+	if editor || !utils.IsTTY() {
+		_ = utils.EditFile(internal.ConfigPath)
+		return
+	}
+
+	section := internal.ProfileSection(profile)
+	fields := []configField{
+		{section: section, key: "base_url"},
+		{section: section, key: "token", secret: true},
+		{section: section, key: "codespace"},
+		{section: section, key: "name"},
+		{section: section, key: "email"},
+		{section: "main", key: "fzf"},
+		{section: "main", key: "clone_opts"},
+	}
+	raw := map[string]map[string]string{}
+	formFields := make([]internal.FormField, 0, len(fields))
+	for _, f := range fields {
+		if raw[f.section] == nil {
+			values, err := internal.ReadConfigValues(f.section)
+			utils.Check(err)
+			raw[f.section] = values
+		}
+		formFields = append(formFields, internal.FormField{Label: f.key, Value: raw[f.section][f.key], Secret: f.secret})
+	}
+
+	values, ok, err := internal.Form("lab config ["+section+"]", formFields, validateConfigForm)
+	utils.Check(err)
+	if !ok {
+		return
+	}
+	changes := map[string]map[string]any{}
+	for i, f := range fields {
+		if values[i] == raw[f.section][f.key] {
+			continue
+		}
+		if changes[f.section] == nil {
+			changes[f.section] = map[string]any{}
+		}
+		var value any = values[i]
+		if f.key == "fzf" {
+			value = map[string]int{"0": 0, "1": 1}[values[i]]
+		}
+		changes[f.section][f.key] = value
+	}
+	for s, values := range changes {
+		utils.Check(internal.SaveConfig(s, values))
+	}
+	fmt.Println("Saved", internal.ConfigPath)
+}
+
+// validateConfigForm the values are in the order of the config fields
+func validateConfigForm(values []string) error {
+	if values[0] != "" && !strings.HasPrefix(values[0], "http") {
+		return errors.New("base_url must start with http:// or https://")
+	}
+	if values[5] != "" && values[5] != "0" && values[5] != "1" {
+		return errors.New("fzf must be 0 or 1")
+	}
+	return nil
 }
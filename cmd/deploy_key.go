@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	deployKeyCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	deployKeyAddCmd.Flags().String("title", "", "deploy key title")
+	deployKeyAddCmd.Flags().String("key", "", "the public ssh key, or @path to read it from the file")
+	deployKeyAddCmd.Flags().Bool("can-push", false, "grant the write access to the repo")
+	deployKeyCmd.AddCommand(deployKeyListCmd)
+	deployKeyCmd.AddCommand(deployKeyAddCmd)
+	rootCmd.AddCommand(deployKeyCmd)
+}
+
+var deployKeyCmd = &cobra.Command{
+	Use:   "deploy-key",
+	Short: "Manage the project deploy keys",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var deployKeyListCmd = &cobra.Command{
+	Annotations: structuredOutput,
+	Use:         "list",
+	Short:       "List the deploy keys of the project",
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		keys := internal.ListDeployKeys(internal.NewClient(), projectFlag(cmd))
+		if len(keys) == 0 {
+			utils.Err("no deploy keys found")
+		}
+
+		printResult(keys, func() {
+			table := utils.NewTable()
+			fmt.Fprintln(table, "ID\tTITLE\tFINGERPRINT\tCAN PUSH\tCREATED")
+			for _, k := range keys {
+				fmt.Fprintf(table, "%d\t%s\t%s\t%t\t%s\n", k.ID, k.Title, k.FingerprintSHA256, k.CanPush, formatTime(k.CreatedAt))
+			}
+			_ = table.Flush()
+		})
+	},
+}
+
+var deployKeyAddCmd = &cobra.Command{
+	Use:   "add --title <title> --key <key> | @<path> [--can-push]",
+	Short: "Add a deploy key to the project",
+	Run:   addDeployKey,
+}
+
+func addDeployKey(cmd *cobra.Command, _ []string) {
+	title, _ := cmd.Flags().GetString("title")
+	key, _ := cmd.Flags().GetString("key")
+	if title == "" || key == "" {
+		utils.Err("deploy key title and key are required, use --title and --key")
+	}
+	// @path read the key from the file, like curl
+	if path, ok := strings.CutPrefix(key, "@"); ok {
+		content, err := os.ReadFile(path)
+		utils.Check(err)
+		key = string(content)
+	}
+	canPush, _ := cmd.Flags().GetBool("can-push")
+
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	utils.Check(internal.AddDeployKey(internal.NewClient(), project, title, strings.TrimSpace(key), canPush))
+	fmt.Println("Deploy key", title, "added")
+}
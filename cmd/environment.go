@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	environmentCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	environmentListCmd.Flags().String("state", "", "only list the environments in the state, available, stopping or stopped")
+	environmentCmd.AddCommand(environmentListCmd)
+	rootCmd.AddCommand(environmentCmd)
+}
+
+var environmentCmd = &cobra.Command{
+	Use:   "environment",
+	Short: "Manage the deployment environments",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var environmentListCmd = &cobra.Command{
+	Use:         "list [--state <state>]",
+	Short:       "List the environments with their last deployment",
+	Run:         listEnvironments,
+	Annotations: structuredOutput,
+}
+
+func listEnvironments(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	state, _ := cmd.Flags().GetString("state")
+	client := internal.NewClient()
+
+	var environments []*gitlab.Environment
+	for _, e := range internal.ListEnvironments(client, project) {
+		if state != "" && e.State != state {
+			continue
+		}
+		// the last deployment is only returned by the single environment api
+		environment, err := internal.GetEnvironment(client, project, e.ID)
+		utils.Check(err)
+		environments = append(environments, environment)
+	}
+	if len(environments) == 0 {
+		utils.Err("no environments found")
+	}
+
+	printResult(environments, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "ENVIRONMENT\tSTATE\tREF\tDEPLOYED\tDEPLOYER")
+		for _, e := range environments {
+			ref, deployedAt, deployer := "-", "-", "-"
+			if d := e.LastDeployment; d != nil {
+				ref, deployedAt = d.Ref, formatTime(d.CreatedAt)
+				if d.User != nil {
+					deployer = d.User.Username
+				}
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.State, ref, deployedAt, deployer)
+		}
+		_ = table.Flush()
+	})
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	fileCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	fileCmd.PersistentFlags().String("ref", "", "branch, tag or commit, default the current branch, or the default branch of --project")
+	fileGetCmd.Flags().Bool("base64", false, "print the content base64 encoded")
+	fileGetCmd.Flags().String("dest", "", "write the content to the file instead of stdout")
+	fileCmd.AddCommand(fileGetCmd)
+	fileCmd.AddCommand(fileBlameCmd)
+	rootCmd.AddCommand(fileCmd)
+}
+
+var fileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "Read the repository files without a clone",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var fileGetCmd = &cobra.Command{
+	Use:   "get <path> [--ref <ref>] [--base64] [--dest <file>]",
+	Short: "Print the content of the file at the ref",
+	Args:  cobra.ExactArgs(1),
+	Run:   getFile,
+}
+
+var fileBlameCmd = &cobra.Command{
+	Use:         "blame <path> [--ref <ref>]",
+	Short:       "Print the blame as file:line: sha (author date) content, for scripts",
+	Args:        cobra.ExactArgs(1),
+	Run:         blameFile,
+	Annotations: structuredOutput,
+}
+
+func getFile(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project, file := projectFile(cmd, args[0])
+	client := internal.NewClient()
+	content, err := internal.GetFile(client, project, file, refFlag(cmd, client, project))
+	utils.Check(err)
+	if encode, _ := cmd.Flags().GetBool("base64"); encode {
+		content = []byte(base64.StdEncoding.EncodeToString(content) + "\n")
+	}
+
+	if dest, _ := cmd.Flags().GetString("dest"); dest != "" {
+		utils.Check(os.WriteFile(dest, content, utils.FilePerm))
+		fmt.Println("Saved", file, "to", dest)
+		return
+	}
+	_, err = os.Stdout.Write(content)
+	utils.Check(err)
+}
+
+func blameFile(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project, file := projectFile(cmd, args[0])
+	client := internal.NewClient()
+	ranges, err := internal.BlameFile(client, project, refFlag(cmd, client, project), file)
+	utils.Check(err)
+
+	printResult(ranges, func() {
+		w := bufio.NewWriter(os.Stdout)
+		defer w.Flush()
+		lineNo := 0
+		for _, r := range ranges {
+			date := "-"
+			if r.Commit.AuthoredDate != nil {
+				date = r.Commit.AuthoredDate.Format(time.RFC3339)
+			}
+			for _, line := range r.Lines {
+				lineNo++
+				fmt.Fprintf(w, "%s:%d: %s (%s %s) %s\n", file, lineNo, r.Commit.ID, r.Commit.AuthorName, date, line)
+			}
+		}
+	})
+}
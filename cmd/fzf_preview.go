@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	rootCmd.AddCommand(fzfPreviewCmd)
+}
+
+// fzfPreviewCmd is run by the fzf preview of internal.FuzzyPreviewFinder
+var fzfPreviewCmd = &cobra.Command{
+	Use:    internal.PreviewCommand + " <socket> <index>",
+	Short:  "Print the preview of a fzf line",
+	Args:   cobra.ExactArgs(2),
+	Hidden: true,
+	Run: func(_ *cobra.Command, args []string) {
+		utils.Check(internal.PrintPreview(args[0], args[1]))
+	},
+}
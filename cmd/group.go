@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	groupListCmd.Flags().String("search", "", "only list the groups matching the search")
+	groupListCmd.Flags().Bool("all", false, "include the groups you are not a member of, require an admin token")
+	groupListCmd.Flags().Bool("print", false, "print the group path, default copy it to the clipboard")
+	groupMembersCmd.Flags().String("access-level", "", "only list the members with the access level, like developer or maintainer")
+	groupCmd.AddCommand(groupListCmd)
+	groupCmd.AddCommand(groupMembersCmd)
+	rootCmd.AddCommand(groupCmd)
+}
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage the gitlab groups",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var groupListCmd = &cobra.Command{
+	Use:   "list [--search <q>] [--all] [--print]",
+	Short: "Fuzzy find a group, copy its full path to the clipboard",
+	Run:   listGroups,
+}
+
+var groupMembersCmd = &cobra.Command{
+	Use:         "members <group-path> [--access-level <level>]",
+	Short:       "List the members of the group with their access levels",
+	Args:        cobra.ExactArgs(1),
+	Run:         listGroupMembers,
+	Annotations: structuredOutput,
+}
+
+func listGroups(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	search, _ := cmd.Flags().GetString("search")
+	all, _ := cmd.Flags().GetBool("all")
+	printPath, _ := cmd.Flags().GetBool("print")
+	groups := internal.ListGroups(internal.NewClient(), search, all)
+	if len(groups) == 0 {
+		utils.Err("no groups found")
+	}
+	lines := make([]string, 0, len(groups))
+	for _, g := range groups {
+		lines = append(lines, g.FullPath)
+	}
+	path := internal.FuzzyFinder(lines)
+	// ctrl-c
+	if path == "" {
+		return
+	}
+	if !printPath {
+		err := utils.CopyToClipboard(path)
+		if err == nil {
+			fmt.Println("Copied", path)
+			return
+		}
+		utils.Warn("copy to clipboard failed: ", err)
+	}
+	fmt.Println(path)
+}
+
+func listGroupMembers(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	var level gitlab.AccessLevelValue
+	if name, _ := cmd.Flags().GetString("access-level"); name != "" {
+		var err error
+		level, err = internal.ParseAccessLevel(name)
+		utils.Check(err)
+	}
+
+	members := []*gitlab.GroupMember{}
+	for _, m := range internal.ListGroupMembers(internal.NewClient(), args[0]) {
+		if level == 0 || m.AccessLevel == level {
+			members = append(members, m)
+		}
+	}
+	printResult(members, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "USERNAME\tNAME\tACCESS\tEXPIRES")
+		for _, m := range members {
+			expires := "-"
+			if m.ExpiresAt != nil {
+				expires = m.ExpiresAt.String()
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", m.Username, m.Name, internal.AccessLevels[m.AccessLevel], expires)
+		}
+		_ = table.Flush()
+	})
+}
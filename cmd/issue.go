@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	issueCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	issueListCmd.Flags().String("group", "", "list the issues of the group instead of a project")
+	issueListCmd.Flags().String("state", "open", "issue state, open, closed or all")
+	issueListCmd.Flags().String("assignee", "", "filter by the assignee username")
+	issueListCmd.Flags().String("label", "", "filter by labels, comma separated")
+	issueListCmd.Flags().String("milestone", "", "filter by the milestone title")
+	issueListCmd.Flags().Bool("print", false, "print the url instead of open it in browser")
+	issueCreateCmd.Flags().StringP("title", "t", "", "issue title")
+	issueCreateCmd.Flags().StringP("description", "d", "", "issue description")
+	issueCreateCmd.Flags().BoolP("edit", "e", false, "edit the title and description in $EDITOR")
+	issueCreateCmd.Flags().StringArray("label", nil, "label of the issue, can be repeated")
+	issueCreateCmd.Flags().String("assignee", "", "assignee username")
+	issueCreateCmd.Flags().String("milestone", "", "milestone title")
+	issueCreateCmd.Flags().String("due", "", "due date, like 2006-01-02")
+	issueCmd.AddCommand(issueListCmd)
+	issueCmd.AddCommand(issueCreateCmd)
+	issueAssignCmd.Flags().StringArray("user", nil, "username, can be repeated")
+	issueAssignCmd.Flags().Bool("me", false, "the current user")
+	issueAssignCmd.Flags().Bool("clear", false, "unassign all the assignees")
+	issueCmd.AddCommand(issueAssignCmd)
+	issueAssignCmd.MarkFlagsMutuallyExclusive("clear", "user")
+	issueAssignCmd.MarkFlagsMutuallyExclusive("clear", "me")
+	issueCloseCmd.Flags().StringP("comment", "m", "", "comment posted before closing, markdown supported")
+	issueCmd.AddCommand(issueCloseCmd)
+	issueCmd.AddCommand(issueReopenCmd)
+	rootCmd.AddCommand(issueCmd)
+}
+
+var issueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Manage the project issues",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var issueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Fuzzy find an issue and open it in browser",
+	Run:   listIssues,
+}
+
+var issueCreateCmd = &cobra.Command{
+	Use:   "create --title <title> [--description <desc> | -e]",
+	Short: "Create a project issue",
+	Run:   createIssue,
+}
+
+var issueAssignCmd = &cobra.Command{
+	Use:   "assign <issue-id> [--user <username>]... [--me] [--clear]",
+	Short: "Add assignees to the issue, or unassign all of them with --clear",
+	Args:  cobra.ExactArgs(1),
+	Run:   assignIssue,
+}
+
+var issueCloseCmd = &cobra.Command{
+	Use:   "close <issue-id>... [--comment <msg>]",
+	Short: "Close the issues, with an optional comment",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setIssuesState(cmd, args, true)
+	},
+}
+
+var issueReopenCmd = &cobra.Command{
+	Use:   "reopen <issue-id>...",
+	Short: "Reopen the closed issues",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setIssuesState(cmd, args, false)
+	},
+}
+
+func listIssues(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	project, _ := cmd.Flags().GetString("project")
+	state, _ := cmd.Flags().GetString("state")
+	assignee, _ := cmd.Flags().GetString("assignee")
+	label, _ := cmd.Flags().GetString("label")
+	milestone, _ := cmd.Flags().GetString("milestone")
+
+	client := internal.NewClient()
+	var issues []*gitlab.Issue
+	if project == "" && group != "" {
+		issues = internal.ListGroupIssues(client, group, state, assignee, label, milestone)
+	} else {
+		issues = internal.ListIssues(client, projectFlag(cmd), state, assignee, label, milestone)
+	}
+	if len(issues) == 0 {
+		utils.Err("no issues found")
+	}
+
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, fmt.Sprintf("#%d %s", issue.IID, issue.Title))
+	}
+	index := internal.FuzzyPreviewFinder(lines, func(i int) string {
+		issue := issues[i]
+		author := ""
+		if issue.Author != nil {
+			author = fmt.Sprintf("%s (@%s)", issue.Author.Name, issue.Author.Username)
+		}
+		return preview(issue.Title, author, issue.Labels, issue.Description)
+	})
+	// ctrl-c
+	if index < 0 {
+		return
+	}
+	openOrPrint(cmd, issues[index].WebURL)
+}
+
+func createIssue(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	title, _ := cmd.Flags().GetString("title")
+	description, _ := cmd.Flags().GetString("description")
+	if edit, _ := cmd.Flags().GetBool("edit"); edit {
+		title, description = editTitle(title, description, "issue")
+	}
+	if title == "" {
+		utils.Err("issue title is required, use --title or -e")
+	}
+	opts := internal.CreateIssueOptions{}
+	opts.Labels, _ = cmd.Flags().GetStringArray("label")
+	opts.Assignee, _ = cmd.Flags().GetString("assignee")
+	opts.Milestone, _ = cmd.Flags().GetString("milestone")
+	opts.DueDate, _ = cmd.Flags().GetString("due")
+
+	client := internal.NewClient()
+	issue, err := internal.CreateIssue(client, project, title, description, opts)
+	utils.Check(err)
+	utils.PrintlnWithColor(utils.ColorFg(fmt.Sprintf("Issue #%d created", issue.IID), internal.MainConfig.ThemeColor))
+	fmt.Println(issue.WebURL)
+}
+
+func assignIssue(cmd *cobra.Command, args []string) {
+	usernames, _ := cmd.Flags().GetStringArray("user")
+	me, _ := cmd.Flags().GetBool("me")
+	clearAll, _ := cmd.Flags().GetBool("clear")
+	if len(usernames) == 0 && !me && !clearAll {
+		utils.Err("assignee is required, use --user, --me or --clear")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	issueID := issueArg(args)
+	client := internal.NewClient()
+
+	assignees := []int{}
+	if !clearAll {
+		issue, err := internal.GetIssue(client, project, issueID)
+		utils.Check(err)
+		userIDs, err := internal.UserIDs(client, usernames, me)
+		utils.Check(err)
+		for _, a := range issue.Assignees {
+			assignees = append(assignees, a.ID)
+		}
+		for _, id := range userIDs {
+			if !slices.Contains(assignees, id) {
+				assignees = append(assignees, id)
+			}
+		}
+	}
+	utils.Check(internal.AssignIssue(client, project, issueID, assignees))
+	if clearAll {
+		fmt.Printf("Issue #%d unassigned\n", issueID)
+	} else {
+		fmt.Printf("Issue #%d assigned\n", issueID)
+	}
+}
+
+// setIssuesState close or reopen every issue of args, the failed ones are printed
+// and the others are still done
+func setIssuesState(cmd *cobra.Command, args []string, closed bool) {
+	issueIDs := make([]int, 0, len(args))
+	for i := range args {
+		issueIDs = append(issueIDs, issueArg(args[i:]))
+	}
+	comment := ""
+	if closed {
+		comment, _ = cmd.Flags().GetString("comment")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+
+	failed := false
+	for _, issueID := range issueIDs {
+		var err error
+		if comment != "" {
+			_, err = internal.AddIssueNote(client, project, issueID, comment)
+		}
+		if err == nil && closed {
+			err = internal.CloseIssue(client, project, issueID)
+		} else if err == nil {
+			err = internal.ReopenIssue(client, project, issueID)
+		}
+		switch {
+		case err != nil:
+			failed = true
+			utils.Warn(fmt.Sprintf("Issue #%d: %s", issueID, err))
+		case closed:
+			fmt.Printf("Issue #%d closed\n", issueID)
+		default:
+			fmt.Printf("Issue #%d reopened\n", issueID)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// issueArg parse the issue id of the first arg, like 12 or #12
+func issueArg(args []string) int {
+	issueID, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		utils.Err("invalid issue id", args[0])
+	}
+	return issueID
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	jobCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	jobCmd.PersistentFlags().Int("pipeline", 0, "pipeline id, default the latest pipeline of the current branch")
+	jobListCmd.Flags().String("status", "", "filter jobs by status, like running, success, failed")
+	jobArtifactsCmd.Flags().Int("job", 0, "job id, default select from the pipeline jobs")
+	jobArtifactsCmd.Flags().String("dest", "artifacts", "directory to extract the artifacts")
+	jobTraceCmd.Flags().Int("job", 0, "job id, default select from the pipeline jobs")
+	jobTraceCmd.Flags().String("name", "", "trace the first job whose name contains the name, case-insensitive")
+	jobTraceCmd.Flags().Int64P("tail", "n", 0, "the number of existing lines to show, 0 the whole log, -1 only new lines, default use tail_line_number in config")
+	jobTraceCmd.Flags().Bool("all", false, "trace all the running jobs of the pipeline")
+	jobTraceCmd.Flags().String("stage", "", "only trace the jobs of the stage")
+	jobTraceCmd.Flags().String("job-name", "", "only trace the jobs with the exact name")
+	jobCmd.AddCommand(jobListCmd)
+	jobCmd.AddCommand(jobArtifactsCmd)
+	jobCmd.AddCommand(jobTraceCmd)
+	rootCmd.AddCommand(jobCmd)
+}
+
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Manage the pipeline jobs",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var jobListCmd = &cobra.Command{
+	Use:         "list [--pipeline <id>]",
+	Short:       "List the jobs of a pipeline",
+	Run:         listJobs,
+	Annotations: structuredOutput,
+}
+
+var jobArtifactsCmd = &cobra.Command{
+	Use:   "artifacts [--job <id>] [--dest <path>]",
+	Short: "Download and extract the artifacts of a job",
+	Run:   downloadArtifacts,
+}
+
+var jobTraceCmd = &cobra.Command{
+	Use:   "trace [--job <id> | --name <name>] [--all] [--stage <stage>] [--job-name <name>] [-n <lines>]",
+	Short: "Trace the log of the running jobs",
+	Run:   traceJobs,
+}
+
+func listJobs(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	status, _ := cmd.Flags().GetString("status")
+	client := internal.NewClient()
+	pipelineID := pipelineFlag(cmd, client, project)
+
+	jobs := []*gitlab.Job{}
+	for _, job := range pipelineJobs(client, project, pipelineID) {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	printResult(jobs, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "ID\tNAME\tSTAGE\tSTATUS\tDURATION\tRUNNER")
+		for _, job := range jobs {
+			duration := time.Duration(job.Duration * float64(time.Second)).Round(time.Second)
+			runner := job.Runner.Description
+			if runner == "" {
+				runner = "-"
+			}
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%s\n", job.ID, job.Name, job.Stage, utils.StatusColor(job.Status), duration, runner)
+		}
+		_ = table.Flush()
+	})
+}
+
+func downloadArtifacts(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	dest, _ := cmd.Flags().GetString("dest")
+	jobID, _ := cmd.Flags().GetInt("job")
+	client := internal.NewClient()
+	if jobID == 0 {
+		job := selectJob(pipelineJobs(client, project, pipelineFlag(cmd, client, project)))
+		// ctrl-c
+		if job == nil {
+			return
+		}
+		jobID = job.ID
+	}
+	err := internal.DownloadArtifacts(client, project, jobID, dest)
+	utils.Check(err)
+	fmt.Println("Artifacts extracted to", dest)
+}
+
+func traceJobs(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	jobID, _ := cmd.Flags().GetInt("job")
+	jobName, _ := cmd.Flags().GetString("name")
+	all, _ := cmd.Flags().GetBool("all")
+	stage, _ := cmd.Flags().GetString("stage")
+	name, _ := cmd.Flags().GetString("job-name")
+	tailLine := internal.MainConfig.TailLineNumber
+	if cmd.Flags().Changed("tail") {
+		tailLine, _ = cmd.Flags().GetInt64("tail")
+	}
+	client := internal.NewClient()
+
+	if jobID > 0 {
+		job, _, err := client.Jobs.GetJob(project, jobID)
+		utils.Check(err)
+		traceJob(cmd.Context(), client, project, job, tailLine)
+		return
+	}
+	pipelineID := pipelineFlag(cmd, client, project)
+	if jobName != "" {
+		job, err := internal.ResolveJobByName(client, project, pipelineID, jobName)
+		utils.Check(err)
+		traceJob(cmd.Context(), client, project, job, tailLine)
+		return
+	}
+
+	jobs := filterJobs(pipelineJobs(client, project, pipelineID), stage, name)
+	if all {
+		if internal.TraceRunningJobs(cmd.Context(), client, project, jobs, tailLine) {
+			fmt.Println("No running jobs")
+		}
+		return
+	}
+	job := selectJob(jobs)
+	// ctrl-c
+	if job == nil {
+		return
+	}
+	traceJob(cmd.Context(), client, project, job, tailLine)
+}
+
+// traceJob trace the job until it finished or ctrl-c
+func traceJob(ctx context.Context, client *gitlab.Client, project string, job *gitlab.Job, tailLine int64) {
+	err := internal.DoTrace(ctx, client, os.Stdout, project, job, tailLine)
+	if !errors.Is(err, context.Canceled) {
+		utils.Check(err)
+	}
+}
+
+// filterJobs keep the jobs of the stage and with the name, empty means no filter
+func filterJobs(jobs []*gitlab.Job, stage, name string) []*gitlab.Job {
+	filtered := make([]*gitlab.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if stage != "" && job.Stage != stage {
+			continue
+		}
+		if name != "" && job.Name != name {
+			continue
+		}
+		filtered = append(filtered, job)
+	}
+	return filtered
+}
+
+// selectJob fuzzy find a job, return nil if canceled
+func selectJob(jobs []*gitlab.Job) *gitlab.Job {
+	if len(jobs) == 0 {
+		utils.Err("no jobs in the pipeline")
+	}
+	lines := make([]string, 0, len(jobs))
+	byLine := make(map[string]*gitlab.Job, len(jobs))
+	for _, job := range jobs {
+		line := fmt.Sprintf("%s [%s] #%d", job.Name, job.Stage, job.ID)
+		lines = append(lines, line)
+		byLine[line] = job
+	}
+	return byLine[internal.FuzzyFinder(lines)]
+}
+
+// pipelineJobs return the pipeline jobs in the order of creation
+func pipelineJobs(client *gitlab.Client, project string, pipelineID int) []*gitlab.Job {
+	jobs := internal.ListPipelineJobs(client, project, pipelineID)
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+// pipelineFlag return the --pipeline flag, default the latest pipeline of the current branch
+func pipelineFlag(cmd *cobra.Command, client *gitlab.Client, project string) int {
+	pipelineID, _ := cmd.Flags().GetInt("pipeline")
+	if pipelineID > 0 {
+		return pipelineID
+	}
+	pipeline, err := internal.LatestPipeline(client, project, internal.CurrentBranch())
+	utils.Check(err)
+	return pipeline.ID
+}
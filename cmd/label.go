@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	labelCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	labelCmd.PersistentFlags().String("group", "", "use the labels of the group instead of the project")
+	labelCreateCmd.Flags().String("name", "", "the label name")
+	labelCreateCmd.Flags().String("color", "", "the label color, like #d9534f")
+	labelCreateCmd.Flags().String("description", "", "the label description")
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelCreateCmd)
+	labelCmd.MarkFlagsMutuallyExclusive("project", "group")
+	rootCmd.AddCommand(labelCmd)
+}
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage the project or group labels",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var labelListCmd = &cobra.Command{
+	Use:         "list [--project <ns/project> | --group <group>]",
+	Short:       "List the labels with their color",
+	Run:         listLabels,
+	Annotations: structuredOutput,
+}
+
+var labelCreateCmd = &cobra.Command{
+	Use:   "create --name <name> --color <color> [--description <text>]",
+	Short: "Create a project or group label",
+	Run:   createLabel,
+}
+
+func listLabels(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	client := internal.NewClient()
+
+	var labels []*gitlab.Label
+	if group != "" {
+		labels = internal.ListGroupLabels(client, group)
+	} else {
+		labels = internal.ListLabels(client, projectFlag(cmd))
+	}
+	if len(labels) == 0 {
+		utils.Err("no labels found")
+	}
+
+	printResult(labels, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "LABEL\tCOLOR\tDESCRIPTION")
+		for _, l := range labels {
+			description := l.Description
+			if description == "" {
+				description = "-"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\n", utils.ColorBg(" "+l.Name+" ", l.Color), l.Color, description)
+		}
+		_ = table.Flush()
+	})
+}
+
+func createLabel(cmd *cobra.Command, _ []string) {
+	name, _ := cmd.Flags().GetString("name")
+	color, _ := cmd.Flags().GetString("color")
+	if name == "" || color == "" {
+		utils.Err("label name and color are required, use --name and --color")
+	}
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	description, _ := cmd.Flags().GetString("description")
+	client := internal.NewClient()
+
+	var label *gitlab.Label
+	var err error
+	if group != "" {
+		label, err = internal.CreateGroupLabel(client, group, name, color, description)
+	} else {
+		label, err = internal.CreateLabel(client, projectFlag(cmd), name, color, description)
+	}
+	utils.Check(err)
+	fmt.Println("Label", utils.ColorBg(" "+label.Name+" ", label.Color), "created")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	milestoneCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	milestoneListCmd.Flags().String("group", "", "list the milestones of the group instead of the project")
+	milestoneListCmd.Flags().String("state", "", "filter by state, active or closed")
+	milestoneCreateCmd.Flags().String("title", "", "the milestone title")
+	milestoneCreateCmd.Flags().String("due", "", "the due date, like 2024-12-31")
+	milestoneCmd.AddCommand(milestoneListCmd)
+	milestoneCmd.AddCommand(milestoneCreateCmd)
+	milestoneListCmd.MarkFlagsMutuallyExclusive("project", "group")
+	rootCmd.AddCommand(milestoneCmd)
+}
+
+var milestoneCmd = &cobra.Command{
+	Use:   "milestone",
+	Short: "Manage the project milestones",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var milestoneListCmd = &cobra.Command{
+	Use:         "list [--project <ns/project> | --group <group>] [--state active|closed]",
+	Short:       "List the milestones of the project or group",
+	Run:         listMilestones,
+	Annotations: structuredOutput,
+}
+
+var milestoneCreateCmd = &cobra.Command{
+	Use:   "create --title <title> [--due <date>]",
+	Short: "Create a milestone of the project, print its id",
+	Run:   createMilestone,
+}
+
+func listMilestones(cmd *cobra.Command, _ []string) {
+	state, _ := cmd.Flags().GetString("state")
+	if state != "" && state != "active" && state != "closed" {
+		utils.Err("invalid state", state, "use active or closed")
+	}
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	client := internal.NewClient()
+
+	var milestones []*gitlab.Milestone
+	if group != "" {
+		milestones = internal.ListGroupMilestones(client, group, state)
+	} else {
+		milestones = internal.ListMilestones(client, projectFlag(cmd), state)
+	}
+	if len(milestones) == 0 {
+		utils.Err("no milestones found")
+	}
+
+	printResult(milestones, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "ID\tTITLE\tSTATE\tSTART\tDUE")
+		for _, m := range milestones {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", m.ID, m.Title, m.State, isoDate(m.StartDate), isoDate(m.DueDate))
+		}
+		_ = table.Flush()
+	})
+}
+
+func createMilestone(cmd *cobra.Command, _ []string) {
+	title, _ := cmd.Flags().GetString("title")
+	if title == "" {
+		utils.Err("milestone title is required, use --title")
+	}
+	internal.Setup(profile)
+	due, _ := cmd.Flags().GetString("due")
+	milestone, err := internal.CreateMilestone(internal.NewClient(), projectFlag(cmd), title, due)
+	utils.Check(err)
+	fmt.Println(milestone.ID)
+}
+
+// isoDate format the date of gitlab, - if not set
+func isoDate(t *gitlab.ISOTime) string {
+	if t == nil {
+		return "-"
+	}
+	return t.String()
+}
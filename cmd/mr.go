@@ -0,0 +1,789 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	mrCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	mrListCmd.Flags().String("state", "open", "merge request state, open, merged, closed or all")
+	mrListCmd.Flags().String("author", "", "filter by the author username")
+	mrListCmd.Flags().StringArray("label", nil, "filter by label, can be repeated")
+	mrListCmd.Flags().Bool("print", false, "print the url instead of open it in browser")
+	mrCreateCmd.Flags().StringP("title", "t", "", "merge request title, default edit it in $EDITOR")
+	mrCreateCmd.Flags().StringP("description", "d", "", "merge request description")
+	mrCreateCmd.Flags().String("target", "", "target branch, default the project default branch")
+	mrCreateCmd.Flags().String("assignee", "", "assignee username")
+	mrCreateCmd.Flags().StringArray("label", nil, "label of the merge request, can be repeated")
+	mrCreateCmd.Flags().Bool("draft", false, "mark the merge request as draft")
+	mrCreateCmd.Flags().Bool("delete-branch", false, "delete the source branch when merged")
+	mrCheckoutCmd.Flags().StringP("branch", "b", "", "local branch name, default the merge request source branch")
+	mrCheckoutCmd.Flags().Bool("https", false, "add the fork remote with https, default use ssh")
+	mrMergeCmd.Flags().Bool("squash", false, "squash the commits into a single commit")
+	mrMergeCmd.Flags().StringP("message", "m", "", "custom merge or squash commit message")
+	mrCmd.AddCommand(mrListCmd)
+	mrCmd.AddCommand(mrCreateCmd)
+	mrCmd.AddCommand(mrCheckoutCmd)
+	mrCmd.AddCommand(mrApproveCmd)
+	mrDiffCmd.Flags().Bool("stat", false, "only print the changed files with the number of added and removed lines")
+	mrDiffCmd.Flags().Bool("word-diff", false, "highlight the changed words instead of the changed lines")
+	mrCmd.AddCommand(mrMergeCmd)
+	mrCommentCmd.Flags().StringP("message", "m", "", "the comment, markdown supported")
+	mrCommentCmd.Flags().BoolP("edit", "e", false, "write the comment in $EDITOR")
+	mrCommentCmd.Flags().Int("reply", 0, "reply to the thread of the note id")
+	mrCmd.AddCommand(mrDiffCmd)
+	mrCmd.AddCommand(mrCommentsCmd)
+	mrCmd.AddCommand(mrCommentCmd)
+	mrStatusCmd.Flags().String("branch", "", "source branch of the open merge request, default the current branch")
+	mrCmd.AddCommand(mrStatusCmd)
+	mrCmd.AddCommand(mrRebaseCmd)
+	mrConflictsCmd.Flags().String("branch", "", "source branch of the open merge request, default the current branch")
+	mrCmd.AddCommand(mrConflictsCmd)
+	for _, c := range []*cobra.Command{mrAssignCmd, mrUnassignCmd} {
+		c.Flags().StringArray("user", nil, "username, can be repeated")
+		c.Flags().Bool("me", false, "the current user")
+		mrCmd.AddCommand(c)
+	}
+	for _, c := range []*cobra.Command{mrLabelAddCmd, mrLabelRemoveCmd} {
+		c.Flags().StringArray("label", nil, "label name, can be repeated")
+		mrLabelCmd.AddCommand(c)
+	}
+	mrCmd.AddCommand(mrLabelCmd)
+	mrCmd.AddCommand(mrDraftCmd)
+	mrCmd.AddCommand(mrReadyCmd)
+	rootCmd.AddCommand(mrCmd)
+}
+
+var mrCmd = &cobra.Command{
+	Use:   "mr",
+	Short: "Manage the project merge requests",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var mrListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Fuzzy find a merge request and open it in browser",
+	Run:   listMergeRequests,
+}
+
+var mrCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a merge request from the current branch",
+	Run:   createMergeRequest,
+}
+
+var mrCheckoutCmd = &cobra.Command{
+	Use:   "checkout <mr-id>",
+	Short: "Check out the merge request branch locally",
+	Args:  cobra.ExactArgs(1),
+	Run:   checkoutMergeRequest,
+}
+
+var mrApproveCmd = &cobra.Command{
+	Use:   "approve <mr-id>",
+	Short: "Approve a merge request",
+	Args:  cobra.ExactArgs(1),
+	Run:   approveMergeRequest,
+}
+
+var mrMergeCmd = &cobra.Command{
+	Use:   "merge <mr-id> [--squash] [--message <msg>]",
+	Short: "Merge a merge request",
+	Args:  cobra.ExactArgs(1),
+	Run:   mergeMergeRequest,
+}
+
+var mrDiffCmd = &cobra.Command{
+	Use:   "diff <id> [--stat] [--word-diff]",
+	Short: "Show the diff of the merge request in $PAGER",
+	Args:  cobra.ExactArgs(1),
+	Run:   diffMergeRequest,
+}
+
+var mrCommentsCmd = &cobra.Command{
+	Use:   "comments <id>",
+	Short: "Show the comment threads of the merge request",
+	Args:  cobra.ExactArgs(1),
+	Run:   listMergeRequestComments,
+}
+
+var mrCommentCmd = &cobra.Command{
+	Use:   "comment <id> [--message <msg> | -e] [--reply <note-id>]",
+	Short: "Comment on the merge request, or reply to a thread",
+	Args:  cobra.ExactArgs(1),
+	Run:   commentMergeRequest,
+}
+
+var mrStatusCmd = &cobra.Command{
+	Use:         "status [<id>] [--branch <branch>]",
+	Short:       "Show if the merge request is ready to merge, exit 1 if not",
+	Args:        cobra.MaximumNArgs(1),
+	Run:         statusMergeRequest,
+	Annotations: structuredOutput,
+}
+
+var mrRebaseCmd = &cobra.Command{
+	Use:   "rebase [<id>]",
+	Short: "Rebase the merge request onto its target branch, default the one of the current branch",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   rebaseMergeRequest,
+}
+
+var mrConflictsCmd = &cobra.Command{
+	Use:   "conflicts [<id>] [--branch <branch>]",
+	Short: "Check if the merge request has conflicts, exit 1 if it has",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   conflictsMergeRequest,
+}
+
+var mrAssignCmd = &cobra.Command{
+	Use:   "assign <id> [--user <username>]... [--me]",
+	Short: "Add assignees to the merge request",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assignMergeRequest(cmd, args, true)
+	},
+}
+
+var mrUnassignCmd = &cobra.Command{
+	Use:   "unassign <id> [--user <username>]... [--me]",
+	Short: "Remove assignees from the merge request, all of them without --user or --me",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assignMergeRequest(cmd, args, false)
+	},
+}
+
+var mrLabelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Add or remove labels of the merge request",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var mrLabelAddCmd = &cobra.Command{
+	Use:   "add <id> --label <label>...",
+	Short: "Add labels to the merge request",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		labelMergeRequest(cmd, args, true)
+	},
+}
+
+var mrLabelRemoveCmd = &cobra.Command{
+	Use:   "remove <id> --label <label>...",
+	Short: "Remove labels from the merge request",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		labelMergeRequest(cmd, args, false)
+	},
+}
+
+var mrDraftCmd = &cobra.Command{
+	Use:   "draft [<id>]",
+	Short: "Mark the merge request as draft, default the one of the current branch",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		draftMergeRequest(cmd, args, true)
+	},
+}
+
+var mrReadyCmd = &cobra.Command{
+	Use:   "ready [<id>]",
+	Short: "Mark the draft merge request as ready, default the one of the current branch",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		draftMergeRequest(cmd, args, false)
+	},
+}
+
+func listMergeRequests(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	state, _ := cmd.Flags().GetString("state")
+	author, _ := cmd.Flags().GetString("author")
+	labels, _ := cmd.Flags().GetStringArray("label")
+
+	opt := &gitlab.ListProjectMergeRequestsOptions{}
+	if author != "" {
+		opt.AuthorUsername = gitlab.Ptr(author)
+	}
+	if len(labels) > 0 {
+		opt.Labels = (*gitlab.LabelOptions)(&labels)
+	}
+	client := internal.NewClient()
+	mrs := internal.ListMergeRequests(client, project, state, opt)
+	if len(mrs) == 0 {
+		utils.Err("no merge requests found")
+	}
+
+	lines := make([]string, 0, len(mrs))
+	for _, mr := range mrs {
+		lines = append(lines, fmt.Sprintf("!%d %s", mr.IID, mr.Title))
+	}
+	index := internal.FuzzyPreviewFinder(lines, func(i int) string {
+		mr := mrs[i]
+		author := ""
+		if mr.Author != nil {
+			author = fmt.Sprintf("%s (@%s)", mr.Author.Name, mr.Author.Username)
+		}
+		return preview(mr.Title, author, mr.Labels, mr.Description)
+	})
+	// ctrl-c
+	if index < 0 {
+		return
+	}
+	openOrPrint(cmd, mrs[index].WebURL)
+}
+
+func createMergeRequest(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	source := internal.CurrentBranch()
+	client := internal.NewClient()
+	if mr := internal.FindMergeRequest(client, project, source); mr != nil {
+		fmt.Printf("Merge request !%d already exists for %s\n", mr.IID, source)
+		fmt.Println(mr.WebURL)
+		return
+	}
+
+	target, _ := cmd.Flags().GetString("target")
+	if target == "" {
+		var err error
+		target, err = internal.DefaultBranch(client, project)
+		utils.Check(err)
+	}
+	title, _ := cmd.Flags().GetString("title")
+	description, _ := cmd.Flags().GetString("description")
+	if title == "" {
+		title, description = editTitle(title, description, "merge request")
+	}
+	opts := internal.CreateMROptions{}
+	opts.Assignee, _ = cmd.Flags().GetString("assignee")
+	opts.Labels, _ = cmd.Flags().GetStringArray("label")
+	opts.Draft, _ = cmd.Flags().GetBool("draft")
+	opts.RemoveSourceBranch, _ = cmd.Flags().GetBool("delete-branch")
+
+	mr, err := internal.CreateMergeRequest(client, project, source, target, title, description, opts)
+	utils.Check(err)
+	utils.PrintlnWithColor(utils.ColorFg(fmt.Sprintf("Merge request !%d created", mr.IID), internal.MainConfig.ThemeColor))
+	fmt.Println(mr.WebURL)
+}
+
+func checkoutMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	client := internal.NewClient()
+	mr, err := internal.GetMergeRequest(client, project, mrID)
+	utils.Check(err)
+
+	remote := internal.CurrentRemote(internal.CurrentBranch())
+	if mr.SourceProjectID != mr.TargetProjectID {
+		source, _, err := client.Projects.GetProject(mr.SourceProjectID, nil)
+		utils.Check(err)
+		remote = source.Namespace.Path
+		if _, err = internal.GitCommand("config", "remote."+remote+".url"); err != nil {
+			gitURL := source.SSHURLToRepo
+			if isHTTPS, _ := cmd.Flags().GetBool("https"); isHTTPS {
+				gitURL = source.HTTPURLToRepo
+			}
+			utils.Check(internal.GitRun("remote", "add", remote, gitURL))
+		}
+	}
+
+	branch, _ := cmd.Flags().GetString("branch")
+	if branch == "" {
+		branch = mr.SourceBranch
+	}
+	utils.Check(internal.GitRun("fetch", remote, mr.SourceBranch))
+	if _, err = internal.GitCommand("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		utils.Check(internal.GitRun("checkout", branch))
+		return
+	}
+	utils.Check(internal.GitRun("checkout", "-b", branch, "--track", remote+"/"+mr.SourceBranch))
+}
+
+func approveMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	client := internal.NewClient()
+	err := internal.ApproveMR(client, project, mrID)
+	utils.Check(err)
+	fmt.Printf("Merge request !%d approved\n", mrID)
+	warnApprovals(client, project, mrID)
+}
+
+func mergeMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	squash, _ := cmd.Flags().GetBool("squash")
+	msg, _ := cmd.Flags().GetString("message")
+	client := internal.NewClient()
+	warnApprovals(client, project, mrID)
+	err := internal.MergeMR(client, project, mrID, squash, msg)
+	utils.Check(err)
+	fmt.Printf("Merge request !%d merged\n", mrID)
+}
+
+func diffMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	diffs, err := internal.GetMRDiff(internal.NewClient(), project, mrID)
+	utils.Check(err)
+	if len(diffs) == 0 {
+		utils.Err(fmt.Sprintf("merge request !%d has no changes", mrID))
+	}
+
+	if stat, _ := cmd.Flags().GetBool("stat"); stat {
+		printDiffStat(diffs)
+		return
+	}
+	var b strings.Builder
+	for _, d := range diffs {
+		b.WriteString(gitDiff(d))
+	}
+	if words, _ := cmd.Flags().GetBool("word-diff"); words {
+		w, wait := utils.StartPager()
+		defer wait()
+		fmt.Fprint(w, wordDiff(b.String()))
+		return
+	}
+	pageDiff(b.String())
+}
+
+// wordDiff replace the removed and added lines of the hunks by the changed words
+func wordDiff(diff string) string {
+	var b strings.Builder
+	var removed, added []string
+	flush := func() {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+		b.WriteString(utils.WordDiff(strings.Join(removed, "\n"), strings.Join(added, "\n")))
+		b.WriteString("\n")
+		removed, added = nil, nil
+	}
+	header, hunk := color.New(color.Bold), color.New(color.FgCyan)
+	inHunk := false
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			inHunk = false
+			header.Fprintln(&b, line)
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+			hunk.Fprintln(&b, line)
+		case !inHunk:
+			header.Fprintln(&b, line)
+		case strings.HasPrefix(line, "-"):
+			removed = append(removed, line[1:])
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		case strings.HasPrefix(line, "\\"):
+			// no newline at end of file
+		default:
+			flush()
+			b.WriteString(strings.TrimPrefix(line, " ") + "\n")
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// pageDiff print the diff highlighted in $PAGER, plain without a terminal
+func pageDiff(diff string) {
+	w, wait := utils.StartPager()
+	defer wait()
+	if !utils.IsTTY() {
+		fmt.Fprint(w, diff)
+		return
+	}
+	if err := quick.Highlight(w, diff, "diff", "terminal256", "monokai"); err != nil {
+		fmt.Fprint(w, diff)
+	}
+}
+
+// gitDiff return the diff of the file with the git diff headers
+func gitDiff(d *gitlab.MergeRequestDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", d.OldPath, d.NewPath)
+	oldPath, newPath := "a/"+d.OldPath, "b/"+d.NewPath
+	switch {
+	case d.NewFile:
+		fmt.Fprintf(&b, "new file mode %s\n", d.BMode)
+		oldPath = "/dev/null"
+	case d.DeletedFile:
+		fmt.Fprintf(&b, "deleted file mode %s\n", d.AMode)
+		newPath = "/dev/null"
+	case d.RenamedFile:
+		fmt.Fprintf(&b, "rename from %s\nrename to %s\n", d.OldPath, d.NewPath)
+	}
+	if d.Diff != "" {
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n%s", oldPath, newPath, d.Diff)
+		if !strings.HasSuffix(d.Diff, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// printDiffStat print the added and removed lines per file, like git diff --stat
+func printDiffStat(diffs []*gitlab.MergeRequestDiff) {
+	table := utils.NewTable()
+	added, removed := 0, 0
+	for _, d := range diffs {
+		a, r := 0, 0
+		for _, line := range strings.Split(d.Diff, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				a++
+			case strings.HasPrefix(line, "-"):
+				r++
+			}
+		}
+		added, removed = added+a, removed+r
+		path := d.NewPath
+		if d.RenamedFile {
+			path = d.OldPath + " => " + d.NewPath
+		}
+		fmt.Fprintf(table, " %s\t| %s %s\n", path, color.GreenString("+%d", a), color.RedString("-%d", r))
+	}
+	_ = table.Flush()
+	fmt.Printf(" %d files changed, %d insertions(+), %d deletions(-)\n", len(diffs), added, removed)
+}
+
+func listMergeRequestComments(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	count := 0
+	for _, d := range internal.ListMRDiscussions(internal.NewClient(), project, mrID) {
+		indent := ""
+		for _, note := range d.Notes {
+			if note.System {
+				continue
+			}
+			count++
+			printNote(note, indent)
+			// the replies of the thread
+			indent = "    "
+		}
+	}
+	if count == 0 {
+		fmt.Printf("Merge request !%d has no comments\n", mrID)
+	}
+}
+
+// printNote print the note header and its indented body
+func printNote(note *gitlab.Note, indent string) {
+	header := fmt.Sprintf("%s#%d %s (@%s) %s", indent, note.ID, note.Author.Name, note.Author.Username, formatTime(note.CreatedAt))
+	if p := note.Position; p != nil && p.NewPath != "" {
+		header += fmt.Sprintf(" %s:%d", p.NewPath, p.NewLine)
+	}
+	if note.Resolved {
+		header += " (resolved)"
+	}
+	utils.PrintlnWithColor(utils.ColorFg(header, internal.MainConfig.ThemeColor))
+	for _, line := range strings.Split(strings.TrimSpace(note.Body), "\n") {
+		fmt.Println(indent + "  " + line)
+	}
+	fmt.Println()
+}
+
+func commentMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	body, _ := cmd.Flags().GetString("message")
+	if edit, _ := cmd.Flags().GetBool("edit"); edit {
+		text, err := utils.EditText(fmt.Sprintf("%s\n\n%s\nWrite the comment above, markdown supported.\n", body, utils.Scissors))
+		utils.Check(err)
+		body = text
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		utils.Err("comment is required, use --message or -e")
+	}
+
+	client := internal.NewClient()
+	var note *gitlab.Note
+	var err error
+	if replyTo, _ := cmd.Flags().GetInt("reply"); replyTo > 0 {
+		note, err = internal.ReplyMRNote(client, project, mrID, replyTo, body)
+	} else {
+		note, err = internal.AddMRNote(client, project, mrID, body)
+	}
+	utils.Check(err)
+	fmt.Printf("Comment #%d added to merge request !%d\n", note.ID, mrID)
+}
+
+// statusMergeRequest print the checks blocking the merge request, exit 1 if it isn't ready to merge
+func statusMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+
+	mrID := mrArgOrBranch(cmd, client, project, args)
+	report, err := internal.MRStatus(client, project, mrID)
+	utils.Check(err)
+
+	result := struct {
+		*internal.MRStatusReport
+		Ready bool `json:"ready"`
+	}{report, report.Ready()}
+	printResult(result, func() {
+		fmt.Printf("!%d %s\n", report.IID, report.Title)
+		table := utils.NewTable()
+		state := report.State
+		if report.Draft {
+			state += " (draft)"
+		}
+		fmt.Fprintf(table, "State\t%s\n", state)
+		pipeline := "none"
+		if report.PipelineStatus != "" {
+			pipeline = utils.StatusIcon(report.PipelineStatus) + " " + utils.StatusColor(report.PipelineStatus)
+		}
+		fmt.Fprintf(table, "Pipeline\t%s\n", pipeline)
+		fmt.Fprintf(table, "Approvals\t%d/%d\n", report.ApprovalsGiven, report.ApprovalsRequired)
+		fmt.Fprintf(table, "Threads\t%d unresolved\n", report.UnresolvedThreads)
+		conflicts := "no"
+		if report.HasConflicts {
+			conflicts = color.RedString("yes")
+		}
+		fmt.Fprintf(table, "Conflicts\t%s\n", conflicts)
+		_ = table.Flush()
+		if report.Ready() {
+			fmt.Println(color.New(color.FgGreen, color.Bold).Sprint("READY"), report.WebURL)
+		} else {
+			fmt.Println(color.New(color.FgRed, color.Bold).Sprint("NOT READY"), report.WebURL)
+		}
+	})
+	if !report.Ready() {
+		os.Exit(1)
+	}
+}
+
+// assignMergeRequest add the users of the flags to the assignees, or remove them if not assign
+func assignMergeRequest(cmd *cobra.Command, args []string, assign bool) {
+	usernames, _ := cmd.Flags().GetStringArray("user")
+	me, _ := cmd.Flags().GetBool("me")
+	if assign && len(usernames) == 0 && !me {
+		utils.Err("assignee is required, use --user or --me")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	client := internal.NewClient()
+	mr, err := internal.GetMergeRequest(client, project, mrID)
+	utils.Check(err)
+	userIDs, err := internal.UserIDs(client, usernames, me)
+	utils.Check(err)
+
+	assignees := make([]int, 0, len(mr.Assignees)+len(userIDs))
+	for _, a := range mr.Assignees {
+		assignees = append(assignees, a.ID)
+	}
+	switch {
+	case assign:
+		for _, id := range userIDs {
+			if !slices.Contains(assignees, id) {
+				assignees = append(assignees, id)
+			}
+		}
+	case len(userIDs) == 0:
+		assignees = assignees[:0]
+	default:
+		assignees = slices.DeleteFunc(assignees, func(id int) bool { return slices.Contains(userIDs, id) })
+	}
+	utils.Check(internal.AssignMR(client, project, mrID, assignees))
+	if assign {
+		fmt.Printf("Merge request !%d assigned\n", mrID)
+	} else {
+		fmt.Printf("Merge request !%d unassigned\n", mrID)
+	}
+}
+
+// labelMergeRequest add the labels of the flag to the merge request, or remove them if not add
+func labelMergeRequest(cmd *cobra.Command, args []string, add bool) {
+	labels, _ := cmd.Flags().GetStringArray("label")
+	if len(labels) == 0 {
+		utils.Err("label is required, use --label")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	mrID := mrArg(args)
+	client := internal.NewClient()
+
+	existing := make(map[string]bool)
+	for _, l := range internal.ListLabels(client, project) {
+		existing[l.Name] = true
+	}
+	for _, l := range labels {
+		if !existing[l] {
+			utils.Err(fmt.Sprintf("label %q does not exist in the project, create it with lab label create", l))
+		}
+	}
+	if add {
+		utils.Check(internal.UpdateMRLabels(client, project, mrID, labels, nil))
+		fmt.Printf("Merge request !%d labeled %s\n", mrID, strings.Join(labels, ", "))
+	} else {
+		utils.Check(internal.UpdateMRLabels(client, project, mrID, nil, labels))
+		fmt.Printf("Merge request !%d unlabeled %s\n", mrID, strings.Join(labels, ", "))
+	}
+}
+
+// draftMergeRequest mark the merge request as draft, or as ready if not draft
+func draftMergeRequest(cmd *cobra.Command, args []string, draft bool) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	mrID := mrArgOrBranch(cmd, client, project, args)
+	mr, err := internal.GetMergeRequest(client, project, mrID)
+	utils.Check(err)
+	state := "ready"
+	if draft {
+		state = "draft"
+	}
+	if mr.Draft == draft {
+		fmt.Printf("Merge request !%d is already %s\n", mrID, state)
+		return
+	}
+	utils.Check(internal.SetMRDraft(client, project, mrID, draft))
+	fmt.Printf("Merge request !%d marked as %s\n", mrID, state)
+}
+
+func conflictsMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	mrID := mrArgOrBranch(cmd, client, project, args)
+	conflicts, err := internal.CheckMRConflicts(client, project, mrID)
+	utils.Check(err)
+	if !conflicts {
+		fmt.Printf("Merge request !%d has no conflicts\n", mrID)
+		return
+	}
+	// the api doesn't list the conflicting files, they are shown in the web ui
+	fmt.Printf("Merge request !%d has conflicts with its target branch\n", mrID)
+	fmt.Printf("Resolve them locally, or at %s/%s/-/merge_requests/%d/conflicts\n", internal.Config.BaseURL, project, mrID)
+	os.Exit(1)
+}
+
+func rebaseMergeRequest(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	mrID := mrArgOrBranch(cmd, client, project, args)
+	utils.Check(internal.RebaseMR(client, project, mrID))
+	fmt.Printf("Rebasing merge request !%d\n", mrID)
+
+	mr, err := internal.WaitRebase(cmd.Context(), client, project, mrID)
+	utils.Check(err)
+	if mr.MergeError != "" {
+		utils.Err(fmt.Sprintf("Rebase of merge request !%d failed: %s", mrID, mr.MergeError))
+	}
+	fmt.Printf("Merge request !%d rebased\n", mrID)
+}
+
+// mrArgOrBranch return the merge request iid of the first arg, default the open merge
+// request of the --branch flag or the current branch
+func mrArgOrBranch(cmd *cobra.Command, client *gitlab.Client, project string, args []string) int {
+	if len(args) > 0 {
+		return mrArg(args)
+	}
+	branch, _ := cmd.Flags().GetString("branch")
+	if branch == "" {
+		branch = internal.CurrentBranch()
+	}
+	mr := internal.FindMergeRequest(client, project, branch)
+	if mr == nil {
+		utils.Err("no open merge request of branch", branch)
+	}
+	return mr.IID
+}
+
+// warnApprovals print a warning if the merge request still requires approvals
+func warnApprovals(client *gitlab.Client, project string, mrID int) {
+	left, err := internal.ApprovalsLeft(client, project, mrID)
+	if err != nil || left == 0 {
+		return
+	}
+	utils.Warn(fmt.Sprintf("Warning: merge request !%d requires %d more approval(s)", mrID, left))
+}
+
+// mrArg parse the merge request iid of the first arg
+func mrArg(args []string) int {
+	mrID, err := strconv.Atoi(strings.TrimPrefix(args[0], "!"))
+	if err != nil {
+		utils.Err("invalid merge request id", args[0])
+	}
+	return mrID
+}
+
+// editTitle edit the title and description in $EDITOR, exit if the title is empty
+func editTitle(title, description, kind string) (string, string) {
+	template := fmt.Sprintf("%s\n\n%s\n\n%s\nThe first line is the %s title, the rest is the description.\n", title, description, utils.Scissors, kind)
+	text, err := utils.EditText(template)
+	utils.Check(err)
+	title, description = utils.ParseTitle(text)
+	if title == "" {
+		utils.Err("aborting due to empty title")
+	}
+	return title, description
+}
+
+// maxPreview the description longer than it is cut in the preview
+const maxPreview = 1000
+
+// preview format the title, author, labels and description excerpt for the fuzzy finder preview
+func preview(title, author string, labels []string, description string) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, title)
+	if author != "" {
+		fmt.Fprintf(&b, "by %s\n", author)
+	}
+	if len(labels) > 0 {
+		badges := make([]string, 0, len(labels))
+		for _, label := range labels {
+			badges = append(badges, utils.ColorBg(" "+label+" ", internal.MainConfig.ThemeColor))
+		}
+		fmt.Fprintln(&b, strings.Join(badges, " "))
+	}
+	if len(description) > maxPreview {
+		description = description[:maxPreview] + "..."
+	}
+	fmt.Fprintf(&b, "\n%s\n", description)
+	return b.String()
+}
+
+// openOrPrint open the url in browser, or print it with --print
+func openOrPrint(cmd *cobra.Command, url string) {
+	printURL, _ := cmd.Flags().GetBool("print")
+	if printURL {
+		fmt.Println(url)
+		return
+	}
+	err := utils.OpenBrowser(url)
+	utils.Check(err)
+}
@@ -16,17 +16,20 @@ func init() {
 	openCmd.Flags().BoolP("pipelines", "p", false, "open pipeline page, only support gitlab")
 	openCmd.Flags().BoolP("merge_requests", "m", false, "open merge_requests page, only support gitlab")
 	openCmd.Flags().StringVar(&subpage, "subpage", "", "open the input subpage")
+	openCmd.Flags().Bool("mr", false, "open the merge requests of the project")
+	openCmd.Flags().Bool("pipeline", false, "open the latest pipeline of the current branch")
+	openCmd.Flags().Int("issue", 0, "open the issue of the project")
 	rootCmd.AddCommand(openCmd)
 }
 
 var openCmd = &cobra.Command{
-	Use:   "open",
+	Use:   "open [--mr | --pipeline | --issue <id>]",
 	Short: "Open the current repo in web browser",
 	Run:   openCurrentRepo,
 }
 
 func openCurrentRepo(cmd *cobra.Command, _ []string) {
-	internal.Setup()
+	internal.Setup(profile)
 	if _, err := internal.CurrentGitRepo(); err != nil {
 		return
 	}
@@ -36,6 +39,10 @@ func openCurrentRepo(cmd *cobra.Command, _ []string) {
 		remote = internal.CurrentRemote(branch)
 	}
 	gitURL := internal.RemoteURL(remote)
+	if url := projectPageURL(cmd, internal.TransferGitURLToProject(gitURL)); url != "" {
+		utils.Check(utils.OpenBrowser(url))
+		return
+	}
 	url := internal.TransferGitURLToURL(gitURL)
 
 	if len(subpage) == 0 {
@@ -58,3 +65,20 @@ func openCurrentRepo(cmd *cobra.Command, _ []string) {
 	err := utils.OpenBrowser(url)
 	utils.Check(err)
 }
+
+// projectPageURL return the url of the page selected by --mr, --pipeline or --issue, empty if none
+func projectPageURL(cmd *cobra.Command, project string) string {
+	projectURL := fmt.Sprintf("%s/%s", internal.Config.BaseURL, project)
+	if mr, _ := cmd.Flags().GetBool("mr"); mr {
+		return projectURL + "/-/merge_requests"
+	}
+	if pl, _ := cmd.Flags().GetBool("pipeline"); pl {
+		pipeline, err := internal.LatestPipeline(internal.NewClient(), project, internal.CurrentBranch())
+		utils.Check(err)
+		return fmt.Sprintf("%s/-/pipelines/%d", projectURL, pipeline.ID)
+	}
+	if issue, _ := cmd.Flags().GetInt("issue"); issue > 0 {
+		return fmt.Sprintf("%s/-/issues/%d", projectURL, issue)
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	pingCmd.Flags().IntP("count", "c", 4, "the number of requests")
+	rootCmd.AddCommand(pingCmd)
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping [--count <n>]",
+	Short: "Check gitlab api is reachable and print the response times, exit 1 if a request failed",
+	Args:  cobra.NoArgs,
+	Run:   ping,
+}
+
+func ping(cmd *cobra.Command, _ []string) {
+	count, _ := cmd.Flags().GetInt("count")
+	if count < 1 {
+		utils.Err("count must be at least 1")
+	}
+	internal.Setup(profile)
+	client := internal.NewClient()
+	fmt.Printf("PING %s/api/v4/version\n", internal.Config.BaseURL)
+
+	var times []time.Duration
+	failed := 0
+	for i := 1; i <= count; i++ {
+		elapsed, err := internal.Ping(client)
+		if err != nil {
+			failed++
+			utils.Warn(fmt.Sprintf("request %d failed: %s", i, err))
+		} else {
+			times = append(times, elapsed)
+			fmt.Printf("request %d: time=%s\n", i, elapsed.Round(time.Millisecond))
+		}
+		if i == count {
+			break
+		}
+		// wait a second between the requests like ping, ctrl-c prints the summary
+		select {
+		case <-time.After(time.Second):
+		case <-cmd.Context().Done():
+			count = i
+		}
+	}
+
+	fmt.Printf("%d requests, %d failed\n", count, failed)
+	if len(times) > 0 {
+		minTime, maxTime, total := times[0], times[0], time.Duration(0)
+		for _, t := range times {
+			minTime, maxTime, total = min(minTime, t), max(maxTime, t), total+t
+		}
+		avg := total / time.Duration(len(times))
+		fmt.Printf("min/max/avg = %s/%s/%s\n", minTime.Round(time.Millisecond), maxTime.Round(time.Millisecond), avg.Round(time.Millisecond))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
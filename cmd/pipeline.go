@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	pipelineCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	pipelineListCmd.Flags().String("ref", "", "filter pipelines by branch or tag")
+	pipelineListCmd.Flags().String("status", "", "filter pipelines by status, like running, success, failed")
+	pipelineListCmd.Flags().Int("limit", 20, "maximum number of pipelines")
+	pipelineListCmd.Flags().String("since", "", "only pipelines updated after, RFC3339 or relative like 7d")
+	pipelineListCmd.Flags().String("until", "", "only pipelines updated before, RFC3339 or relative like 1w")
+	pipelineTriggerCmd.Flags().String("ref", "", "branch or tag to run the pipeline, default the current branch")
+	pipelineTriggerCmd.Flags().StringArray("var", nil, "pipeline variable KEY=VALUE, can be repeated")
+	pipelineCancelCmd.Flags().Bool("latest", false, "use the latest pipeline of the current branch")
+	pipelineRetryCmd.Flags().Bool("latest", false, "use the latest pipeline of the current branch")
+	pipelineStatusCmd.Flags().Int("pipeline", 0, "pipeline id, default the latest pipeline of the current branch")
+	pipelineStatusCmd.Flags().BoolP("watch", "w", false, "refresh the status until the pipeline finished")
+	pipelineCmd.AddCommand(pipelineListCmd)
+	pipelineCmd.AddCommand(pipelineTriggerCmd)
+	pipelineCmd.AddCommand(pipelineCancelCmd)
+	pipelineCmd.AddCommand(pipelineRetryCmd)
+	pipelineCmd.AddCommand(pipelineStatusCmd)
+	rootCmd.AddCommand(pipelineCmd)
+}
+
+var pipelineCmd = &cobra.Command{
+	Use:     "pipeline",
+	Aliases: []string{"pl"},
+	Short:   "Manage the project pipelines",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var pipelineListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the recent pipelines of the project",
+	Run:         listPipelines,
+	Annotations: structuredOutput,
+}
+
+var pipelineTriggerCmd = &cobra.Command{
+	Use:   "trigger",
+	Short: "Run a new pipeline on a branch or tag",
+	Run:   triggerPipeline,
+}
+
+var pipelineCancelCmd = &cobra.Command{
+	Use:   "cancel [<pipeline-id>] [--latest]",
+	Short: "Cancel the running jobs of a pipeline",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updatePipeline(cmd, args, internal.CancelPipeline)
+	},
+}
+
+var pipelineRetryCmd = &cobra.Command{
+	Use:   "retry [<pipeline-id>] [--latest]",
+	Short: "Retry the failed jobs of a pipeline",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updatePipeline(cmd, args, internal.RetryPipeline)
+	},
+}
+
+var pipelineStatusCmd = &cobra.Command{
+	Use:         "status [--pipeline <id>] [--watch]",
+	Short:       "Show the status of the pipeline jobs, refreshed until it finished with --watch",
+	Run:         statusPipeline,
+	Annotations: structuredOutput,
+}
+
+func listPipelines(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	ref, _ := cmd.Flags().GetString("ref")
+	status, _ := cmd.Flags().GetString("status")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 1 {
+		utils.Err("--limit must be at least 1")
+	}
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+
+	opt := &gitlab.ListProjectPipelinesOptions{}
+	if since != "" {
+		t, err := utils.ParseRelativeTime(since)
+		utils.Check(err)
+		opt.UpdatedAfter = gitlab.Ptr(t)
+	}
+	if until != "" {
+		t, err := utils.ParseRelativeTime(until)
+		utils.Check(err)
+		opt.UpdatedBefore = gitlab.Ptr(t)
+	}
+	if ref != "" {
+		opt.Ref = gitlab.Ptr(ref)
+	}
+	if status != "" {
+		opt.Status = gitlab.Ptr(gitlab.BuildStateValue(status))
+	}
+	client := internal.NewClient()
+	pipelines := internal.PipelineList(client, project, opt, limit)
+
+	printResult(pipelines, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "ID\tSTATUS\tREF\tCREATED\tDURATION")
+		for _, p := range pipelines {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", p.ID, utils.StatusColor(p.Status), p.Ref, formatTime(p.CreatedAt), pipelineDuration(p))
+		}
+		_ = table.Flush()
+	})
+}
+
+func triggerPipeline(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	ref, _ := cmd.Flags().GetString("ref")
+	if ref == "" {
+		ref = internal.CurrentBranch()
+	}
+	vars, _ := cmd.Flags().GetStringArray("var")
+	variables := make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			utils.Err("invalid variable", v, "use KEY=VALUE")
+		}
+		variables[key] = value
+	}
+
+	client := internal.NewClient()
+	pipeline, err := internal.TriggerPipeline(client, project, ref, variables)
+	utils.Check(err)
+	utils.PrintlnWithColor(utils.ColorFg(fmt.Sprintf("Pipeline #%d created", pipeline.ID), internal.MainConfig.ThemeColor))
+	fmt.Println(pipeline.WebURL)
+}
+
+func updatePipeline(cmd *cobra.Command, args []string, update func(*gitlab.Client, any, int) (*gitlab.Pipeline, error)) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	pipelineID := pipelineArg(cmd, client, project, args)
+	pipeline, err := update(client, project, pipelineID)
+	utils.Check(err)
+	fmt.Printf("Pipeline #%d %s\n", pipeline.ID, utils.StatusColor(pipeline.Status))
+}
+
+// pipelineStatus is the --output of lab pipeline status
+type pipelineStatus struct {
+	*gitlab.Pipeline
+	Jobs []*gitlab.Job `json:"jobs"`
+}
+
+func statusPipeline(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	watch, _ := cmd.Flags().GetBool("watch")
+	client := internal.NewClient()
+	pipelineID := pipelineFlag(cmd, client, project)
+
+	if !watch {
+		pipeline, err := internal.GetPipeline(client, project, pipelineID)
+		utils.Check(err)
+		jobs := pipelineJobs(client, project, pipelineID)
+		printResult(pipelineStatus{pipeline, jobs}, func() {
+			fmt.Print(renderPipelineStatus(pipeline, jobs))
+		})
+		return
+	}
+
+	out := colorable.NewColorableStdout()
+	lines := 0
+	err := internal.WatchPipeline(cmd.Context(), client, project, pipelineID, func(pipeline *gitlab.Pipeline, jobs []*gitlab.Job) {
+		sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+		status := renderPipelineStatus(pipeline, jobs)
+		// move the cursor up to the first line of the previous status and clear it
+		if lines > 0 {
+			fmt.Fprintf(out, "\x1b[%dA\x1b[J", lines)
+		}
+		fmt.Fprint(out, status)
+		lines = strings.Count(status, "\n")
+	})
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	utils.Check(err)
+}
+
+// renderPipelineStatus return the pipeline line and the table of its jobs
+func renderPipelineStatus(pipeline *gitlab.Pipeline, jobs []*gitlab.Job) string {
+	buf := &strings.Builder{}
+	duration := time.Duration(pipeline.Duration) * time.Second
+	if internal.IsRunning(pipeline.Status) && pipeline.CreatedAt != nil {
+		duration = time.Since(*pipeline.CreatedAt).Round(time.Second)
+	}
+	fmt.Fprintf(buf, "Pipeline #%d %s on %s (%s)\n\n", pipeline.ID, utils.StatusColor(pipeline.Status), pipeline.Ref, duration)
+	table := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "STAGE\tNAME\tSTATUS\tDURATION")
+	for _, job := range jobs {
+		duration := time.Duration(job.Duration * float64(time.Second)).Round(time.Second)
+		if job.Status == "running" && job.StartedAt != nil {
+			duration = time.Since(*job.StartedAt).Round(time.Second)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", job.Stage, job.Name, utils.StatusColor(job.Status), duration)
+	}
+	_ = table.Flush()
+	return buf.String()
+}
+
+// pipelineArg return the pipeline id of the args, or the latest pipeline of the current branch with --latest
+func pipelineArg(cmd *cobra.Command, client *gitlab.Client, project string, args []string) int {
+	latest, _ := cmd.Flags().GetBool("latest")
+	if latest {
+		pipeline, err := internal.LatestPipeline(client, project, internal.CurrentBranch())
+		utils.Check(err)
+		return pipeline.ID
+	}
+	if len(args) == 0 {
+		utils.Err("pipeline id is required, or use --latest")
+	}
+	pipelineID, err := strconv.Atoi(args[0])
+	if err != nil {
+		utils.Err("invalid pipeline id", args[0])
+	}
+	return pipelineID
+}
+
+// pipelineDuration the list api has no duration, use the time between created and last updated
+func pipelineDuration(p *gitlab.PipelineInfo) string {
+	if p.CreatedAt == nil {
+		return "-"
+	}
+	end := time.Now()
+	if !internal.IsRunning(p.Status) && p.UpdatedAt != nil {
+		end = *p.UpdatedAt
+	}
+	return end.Sub(*p.CreatedAt).Round(time.Second).String()
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	projectArchiveCmd.Flags().BoolP("yes", "y", false, "archive without confirmation")
+	projectForkCmd.Flags().String("namespace", "", "the target namespace, default your user namespace")
+	projectForkCmd.Flags().Bool("clone", false, "clone the fork into the codespace")
+	projectForkCmd.Flags().Bool("https", false, "clone with https, default use ssh")
+	projectTransferCmd.Flags().String("namespace", "", "the target namespace")
+	projectTransferCmd.Flags().BoolP("yes", "y", false, "transfer without confirmation")
+	projectRenameCmd.Flags().String("name", "", "the new project name")
+	projectRenameCmd.Flags().String("path", "", "the new project path, the last part of the url")
+	projectCreateCmd.Flags().String("namespace", "", "the namespace of the project, default your user namespace")
+	projectCreateCmd.Flags().String("description", "", "the project description")
+	projectCreateCmd.Flags().String("visibility", "private", "the project visibility, private, internal or public")
+	projectCreateCmd.Flags().String("template", "", "create the project from the gitlab project template, like rails or spring")
+	projectCreateCmd.Flags().Bool("init", false, "git init the current directory, commit and push it to the new project")
+	projectCreateCmd.Flags().Bool("https", false, "add the remote with https, default use ssh")
+	projectCreateCmd.MarkFlagsMutuallyExclusive("init", "template")
+	projectDeleteCmd.Flags().BoolP("yes", "y", false, "delete without typing the project path")
+	projectDeleteCmd.Flags().Bool("force", false, "delete the project even if it is not archived")
+	projectCmd.AddCommand(projectInfoCmd)
+	projectCmd.AddCommand(projectArchiveCmd)
+	projectCmd.AddCommand(projectUnarchiveCmd)
+	projectCmd.AddCommand(projectForkCmd)
+	projectCmd.AddCommand(projectTransferCmd)
+	projectCmd.AddCommand(projectCreateCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+	projectCmd.AddCommand(projectRenameCmd)
+	projectCmd.AddCommand(projectStarCmd)
+	projectCmd.AddCommand(projectUnstarCmd)
+	projectCmd.AddCommand(projectStarsCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the gitlab projects",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var projectInfoCmd = &cobra.Command{
+	Use:         "info [<namespace/project>]",
+	Short:       "Show the project metadata, default the current repo",
+	Args:        cobra.MaximumNArgs(1),
+	Run:         projectInfo,
+	Annotations: structuredOutput,
+}
+
+var projectArchiveCmd = &cobra.Command{
+	Use:   "archive [<namespace/project>] [--yes]",
+	Short: "Archive the project, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		internal.Setup(profile)
+		project := projectArg(args)
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes && !utils.Confirm(fmt.Sprintf("Archive %s? It becomes read-only and its pipeline schedules stop.", project)) {
+			return
+		}
+		utils.Check(internal.ArchiveProject(internal.NewClient(), project))
+		fmt.Println("Project", project, "archived")
+	},
+}
+
+var projectUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive [<namespace/project>]",
+	Short: "Unarchive the project, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		internal.Setup(profile)
+		project := projectArg(args)
+		utils.Check(internal.UnarchiveProject(internal.NewClient(), project))
+		fmt.Println("Project", project, "unarchived")
+	},
+}
+
+var projectStarCmd = &cobra.Command{
+	Use:   "star [<namespace/project>]",
+	Short: "Star the project, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		internal.Setup(profile)
+		p, err := internal.StarProject(internal.NewClient(), projectArg(args))
+		utils.Check(err)
+		fmt.Printf("Project %s starred, %d stars\n", p.PathWithNamespace, p.StarCount)
+	},
+}
+
+var projectUnstarCmd = &cobra.Command{
+	Use:   "unstar [<namespace/project>]",
+	Short: "Unstar the project, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		internal.Setup(profile)
+		p, err := internal.UnstarProject(internal.NewClient(), projectArg(args))
+		utils.Check(err)
+		fmt.Printf("Project %s unstarred, %d stars\n", p.PathWithNamespace, p.StarCount)
+	},
+}
+
+var projectStarsCmd = &cobra.Command{
+	Annotations: structuredOutput,
+	Use:         "stars",
+	Short:       "List the projects starred by you",
+	Args:        cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		projects, err := internal.StarredProjects(internal.NewClient())
+		utils.Check(err)
+		printResult(projects, func() {
+			table := utils.NewTable()
+			fmt.Fprintln(table, "PROJECT\tSTARS\tLAST ACTIVITY")
+			for _, p := range projects {
+				fmt.Fprintf(table, "%s\t%d\t%s\n", p.PathWithNamespace, p.StarCount, formatTime(p.LastActivityAt))
+			}
+			_ = table.Flush()
+		})
+	},
+}
+
+var projectForkCmd = &cobra.Command{
+	Use:   "fork [<namespace/project>] [--namespace <target>] [--clone]",
+	Short: "Fork the project, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   forkProject,
+}
+
+var projectTransferCmd = &cobra.Command{
+	Use:   "transfer [<namespace/project>] --namespace <target> [--yes]",
+	Short: "Move the project to another namespace, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   transferProject,
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create <name> [--namespace <ns>] [--visibility <visibility>] [--init | --template <name>]",
+	Short: "Create a new project, print its url",
+	Args:  cobra.ExactArgs(1),
+	Run:   createProject,
+}
+
+func createProject(cmd *cobra.Command, args []string) {
+	visibility, _ := cmd.Flags().GetString("visibility")
+	switch visibility {
+	case "private", "internal", "public":
+	default:
+		utils.Err("invalid visibility", visibility, "use private, internal or public")
+	}
+	internal.Setup(profile)
+	namespace, _ := cmd.Flags().GetString("namespace")
+	description, _ := cmd.Flags().GetString("description")
+	template, _ := cmd.Flags().GetString("template")
+	initRepo, _ := cmd.Flags().GetBool("init")
+	// check before creating the project, it can't be pushed to an origin already set
+	if initRepo && internal.IsInsideWorkTree() {
+		if origin, _ := internal.GitCommand("config", "--get", "remote.origin.url"); origin != "" {
+			utils.Err("the repo already has the origin remote", strings.TrimSpace(origin))
+		}
+	}
+	client := internal.NewClient()
+
+	opts := &gitlab.CreateProjectOptions{
+		Name:       gitlab.Ptr(args[0]),
+		Visibility: gitlab.Ptr(gitlab.VisibilityValue(visibility)),
+	}
+	if namespace != "" {
+		id, err := internal.NamespaceID(client, namespace)
+		utils.Check(err)
+		opts.NamespaceID = gitlab.Ptr(id)
+	}
+	if description != "" {
+		opts.Description = gitlab.Ptr(description)
+	}
+	if template != "" {
+		opts.TemplateName = gitlab.Ptr(template)
+	}
+	p, err := internal.CreateProject(client, opts)
+	utils.Check(err)
+	fmt.Println("Created", p.PathWithNamespace)
+	fmt.Println(p.WebURL)
+
+	if initRepo {
+		gitURL := p.SSHURLToRepo
+		if isHTTPS, _ := cmd.Flags().GetBool("https"); isHTTPS {
+			gitURL = p.HTTPURLToRepo
+		}
+		pushInitialCommit(gitURL)
+	}
+}
+
+// pushInitialCommit git init the current directory if it isn't in a repo, commit the files
+// when there is no commit yet, and push it to the remote url as origin
+func pushInitialCommit(gitURL string) {
+	if !internal.IsInsideWorkTree() {
+		utils.Check(internal.GitRun("init"))
+	}
+	if _, err := internal.GitCommand("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		utils.Check(internal.GitRun("add", "--all"))
+		utils.Check(internal.GitRun("commit", "--allow-empty", "-m", "Initial commit"))
+	}
+	utils.Check(internal.GitRun("remote", "add", "origin", gitURL))
+	utils.Check(internal.GitRun("push", "-u", "origin", "HEAD"))
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete <namespace/project> [--force] [--yes]",
+	Short: "Delete the project, only the archived ones unless --force",
+	Args:  cobra.ExactArgs(1),
+	Run:   deleteProject,
+}
+
+func deleteProject(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	force, _ := cmd.Flags().GetBool("force")
+	yes, _ := cmd.Flags().GetBool("yes")
+	client := internal.NewClient()
+	p, err := internal.GetProject(client, args[0])
+	utils.Check(err)
+	if !p.Archived && !force {
+		utils.Err(p.PathWithNamespace, "is not archived, archive it first or use --force")
+	}
+
+	utils.Warn("Warning: deleting " + p.PathWithNamespace + " is permanent, its repository, issues and merge requests can't be restored")
+	if !yes && !utils.ConfirmInput("Delete "+p.PathWithNamespace+"?", p.PathWithNamespace) {
+		return
+	}
+	utils.Check(internal.DeleteProject(client, p.ID))
+	fmt.Println("Project", p.PathWithNamespace, "deleted")
+}
+
+func transferProject(cmd *cobra.Command, args []string) {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	if namespace == "" {
+		utils.Err("namespace is required, use --namespace")
+	}
+	internal.Setup(profile)
+	project := projectArg(args)
+	yes, _ := cmd.Flags().GetBool("yes")
+	if !yes && !utils.Confirm(fmt.Sprintf("Transfer %s to %s? The project url changes.", project, namespace)) {
+		return
+	}
+	p, err := internal.TransferProject(internal.NewClient(), project, namespace)
+	utils.Check(err)
+	fmt.Println("Transferred", project, "to", p.PathWithNamespace)
+	fmt.Println(p.WebURL)
+	utils.Warn("Warning: update the remote url of the local clones, like `git remote set-url origin " + p.SSHURLToRepo + "`")
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename [<namespace/project>] [--name <name>] [--path <path>]",
+	Short: "Rename the project name or path, default the current repo",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   renameProject,
+}
+
+func renameProject(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("name")
+	path, _ := cmd.Flags().GetString("path")
+	if name == "" && path == "" {
+		utils.Err("name or path is required, use --name or --path")
+	}
+	internal.Setup(profile)
+	project := projectArg(args)
+	if path != "" {
+		utils.Warn("Warning: renaming the path changes the project url, the existing clones must update their remote url")
+	}
+	p, err := internal.RenameProject(internal.NewClient(), project, name, path)
+	utils.Check(err)
+	fmt.Println("Renamed", project, "to", p.PathWithNamespace)
+	fmt.Println(p.WebURL)
+	if p.PathWithNamespace == project || internal.Config.Codespace == "" {
+		return
+	}
+
+	// the clone in the codespace is named after the project path
+	oldDir, newDir := codespacePath(project), codespacePath(p.PathWithNamespace)
+	if !utils.FileExists(oldDir) || utils.FileExists(newDir) {
+		return
+	}
+	if utils.Confirm(fmt.Sprintf("Rename the local directory %s to %s?", oldDir, newDir)) {
+		utils.Check(os.Rename(oldDir, newDir))
+	}
+}
+
+func forkProject(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	namespace, _ := cmd.Flags().GetString("namespace")
+	cloneFork, _ := cmd.Flags().GetBool("clone")
+	isHTTPS, _ := cmd.Flags().GetBool("https")
+	client := internal.NewClient()
+	project, err := internal.GetProject(client, projectArg(args))
+	utils.Check(err)
+
+	fork, err := internal.ExistingFork(client, project, namespace)
+	utils.Check(err)
+	if fork != nil {
+		fmt.Println("Fork already exists:", fork.WebURL)
+		return
+	}
+	fork, err = internal.ForkProject(client, project.ID, namespace)
+	utils.Check(err)
+	fmt.Println("Forked", project.PathWithNamespace, "to", fork.PathWithNamespace)
+	fmt.Println("ssh:  ", fork.SSHURLToRepo)
+	fmt.Println("https:", fork.HTTPURLToRepo)
+	if cloneFork {
+		utils.PrintlnWithColor(utils.ColorFg("Cloning "+fork.PathWithNamespace, internal.MainConfig.ThemeColor))
+		clone(fork.PathWithNamespace, isHTTPS, false)
+	}
+}
+
+func projectInfo(_ *cobra.Command, args []string) {
+	internal.Setup(profile)
+	client := internal.NewClient()
+	p, err := internal.GetProject(client, projectArg(args))
+	utils.Check(err)
+	mrs, err := internal.OpenMergeRequestCount(client, p.ID)
+	utils.Check(err)
+
+	printResult(p, func() {
+		description := p.Description
+		if description == "" {
+			description = "-"
+		}
+		fmt.Println(utils.ColorFg(p.PathWithNamespace, internal.MainConfig.ThemeColor))
+		table := utils.NewTable()
+		fmt.Fprintf(table, "description\t%s\n", description)
+		fmt.Fprintf(table, "default branch\t%s\n", p.DefaultBranch)
+		fmt.Fprintf(table, "visibility\t%s\n", p.Visibility)
+		fmt.Fprintf(table, "stars\t%d\n", p.StarCount)
+		fmt.Fprintf(table, "forks\t%d\n", p.ForksCount)
+		fmt.Fprintf(table, "open issues\t%d\n", p.OpenIssuesCount)
+		fmt.Fprintf(table, "open merge requests\t%d\n", mrs)
+		fmt.Fprintf(table, "last activity\t%s\n", formatTime(p.LastActivityAt))
+		fmt.Fprintf(table, "ssh\t%s\n", p.SSHURLToRepo)
+		fmt.Fprintf(table, "https\t%s\n", p.HTTPURLToRepo)
+		_ = table.Flush()
+	})
+}
+
+// projectArg return the project of the args, default is the project of the current repo remote
+func projectArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return internal.CurrentProject()
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	protectCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	protectBranchCmd.Flags().String("push-access", "", "who can push, none, developer or maintainer")
+	protectBranchCmd.Flags().String("merge-access", "", "who can merge, none, developer or maintainer")
+	protectBranchCmd.Flags().Bool("allow-force-push", false, "allow the users who can push to force push")
+	protectTagCmd.Flags().String("create-access", "", "who can create the tags, none, developer or maintainer")
+	protectCmd.AddCommand(protectBranchCmd)
+	protectCmd.AddCommand(protectTagCmd)
+	rootCmd.AddCommand(protectCmd)
+}
+
+var protectCmd = &cobra.Command{
+	Use:   "protect",
+	Short: "Protect the project branches and tags",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var protectBranchCmd = &cobra.Command{
+	Use:   "branch <name> [--push-access <level>] [--merge-access <level>]",
+	Short: "Protect the branch or the wildcard branch pattern, gitlab defaults to maintainer",
+	Args:  cobra.ExactArgs(1),
+	Run:   protectBranch,
+}
+
+var protectTagCmd = &cobra.Command{
+	Use:   "tag <pattern> [--create-access <level>]",
+	Short: "Protect the tag or the wildcard tag pattern, gitlab defaults to maintainer",
+	Args:  cobra.ExactArgs(1),
+	Run:   protectTag,
+}
+
+func protectBranch(cmd *cobra.Command, args []string) {
+	opts := &gitlab.ProtectRepositoryBranchesOptions{
+		PushAccessLevel:  accessLevelFlag(cmd, "push-access"),
+		MergeAccessLevel: accessLevelFlag(cmd, "merge-access"),
+	}
+	if forcePush, _ := cmd.Flags().GetBool("allow-force-push"); forcePush {
+		opts.AllowForcePush = gitlab.Ptr(true)
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	protected, err := internal.ProtectBranch(internal.NewClient(), project, args[0], opts)
+	utils.Check(err)
+
+	fmt.Printf("Branch %s protected\n", protected.Name)
+	table := utils.NewTable()
+	fmt.Fprintf(table, "push\t%s\n", branchAccess(protected.PushAccessLevels))
+	fmt.Fprintf(table, "merge\t%s\n", branchAccess(protected.MergeAccessLevels))
+	fmt.Fprintf(table, "force push\t%t\n", protected.AllowForcePush)
+	_ = table.Flush()
+}
+
+func protectTag(cmd *cobra.Command, args []string) {
+	opts := &gitlab.ProtectRepositoryTagsOptions{CreateAccessLevel: accessLevelFlag(cmd, "create-access")}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	protected, err := internal.ProtectTag(internal.NewClient(), project, args[0], opts)
+	utils.Check(err)
+
+	levels := make([]string, 0, len(protected.CreateAccessLevels))
+	for _, l := range protected.CreateAccessLevels {
+		levels = append(levels, l.AccessLevelDescription)
+	}
+	fmt.Printf("Tag %s protected\n", protected.Name)
+	table := utils.NewTable()
+	fmt.Fprintf(table, "create\t%s\n", orDash(strings.Join(levels, ", ")))
+	_ = table.Flush()
+}
+
+// accessLevelFlag parse the access level of the flag, nil if not set so gitlab uses its default.
+// none means no one is allowed
+func accessLevelFlag(cmd *cobra.Command, name string) *gitlab.AccessLevelValue {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return nil
+	}
+	if strings.EqualFold(value, "none") {
+		return gitlab.Ptr(gitlab.NoPermissions)
+	}
+	level, err := internal.ParseAccessLevel(value)
+	if err != nil {
+		utils.Err(fmt.Sprintf("invalid --%s: %s", name, err))
+	}
+	return gitlab.Ptr(level)
+}
+
+// branchAccess join the descriptions of the access levels, like Maintainers
+func branchAccess(levels []*gitlab.BranchAccessDescription) string {
+	descriptions := make([]string, 0, len(levels))
+	for _, l := range levels {
+		descriptions = append(descriptions, l.AccessLevelDescription)
+	}
+	return orDash(strings.Join(descriptions, ", "))
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+// the status of a pulled repo
+const (
+	pullOK        = "OK"
+	pullConflicts = "CONFLICTS"
+	pullSkipped   = "SKIPPED"
+	pullNotRepo   = "NOT A REPO"
+	pullFailed    = "FAILED"
+)
+
+func init() {
+	pullCmd.Flags().String("group", "", "only pull the repos of the group")
+	pullCmd.Flags().IntVarP(&maxDepth, "depth", "d", defaultDepth, "maximum depth to filepath walk")
+	rootCmd.AddCommand(pullCmd)
+}
+
+var pullCmd = &cobra.Command{
+	Use:   "pull [--group <group>]",
+	Short: "git pull --ff-only all repos in your codespace",
+	Run:   pullCodespace,
+}
+
+func pullCodespace(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	codespace := internal.Config.Codespace
+	if codespace == "" {
+		utils.Err("use <lab config> to set codespace first")
+	}
+	dir := codespace
+	if group != "" {
+		dir = filepath.Join(codespace, codespaceHost(), group)
+	}
+	repos, err := codespaceRepos(dir, maxDepth)
+	utils.Check(err)
+	if len(repos) == 0 {
+		utils.Err("no repos in", dir)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	counts := map[string]int{}
+	sem := make(chan struct{}, max(internal.MainConfig.NumWorkers, 1))
+	for _, repo := range repos {
+		if cmd.Context().Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(repo string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			status, detail := pullRepo(filepath.Join(dir, repo))
+			mu.Lock()
+			defer mu.Unlock()
+			counts[status]++
+			printPullStatus(repo, status, detail)
+		}(repo)
+	}
+	wg.Wait()
+
+	summary := make([]string, 0, len(counts))
+	for _, status := range []string{pullOK, pullConflicts, pullSkipped, pullNotRepo, pullFailed} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	fmt.Println(strings.Join(summary, ", "))
+}
+
+// pullRepo git pull --ff-only the repo, the repos with uncommitted changes are skipped
+func pullRepo(path string) (status, detail string) {
+	dirty, err := internal.IsDirty(path)
+	if err != nil {
+		return pullNotRepo, ""
+	}
+	if dirty {
+		return pullSkipped, "uncommitted changes, skipped"
+	}
+	output, err := internal.PullFFOnly(path)
+	if err == nil {
+		return pullOK, ""
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	detail = lines[len(lines)-1]
+	if strings.Contains(output, "fast-forward") || strings.Contains(output, "diverg") {
+		return pullConflicts, detail
+	}
+	return pullFailed, detail
+}
+
+func printPullStatus(repo, status, detail string) {
+	switch status {
+	case pullOK:
+		status = color.GreenString(status)
+	case pullSkipped:
+		utils.Warn(repo, ": ", detail)
+		return
+	default:
+		status = color.RedString(status)
+	}
+	if detail != "" {
+		detail = " " + detail
+	}
+	fmt.Printf("%s %s%s\n", status, repo, detail)
+}
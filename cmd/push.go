@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	pushCmd.Flags().Bool("draft", false, "mark the merge request as draft")
+	pushCmd.Flags().String("target", "", "target branch, default the project default branch")
+	pushCmd.Flags().StringP("title", "t", "", "merge request title, default edit it in $EDITOR for a new merge request")
+	pushCmd.Flags().StringP("description", "d", "", "merge request description")
+	pushCmd.Flags().StringArray("label", nil, "label of the merge request, can be repeated")
+	rootCmd.AddCommand(pushCmd)
+}
+
+var pushCmd = &cobra.Command{
+	Use:   "push [--draft] [--target <branch>] [--title <title>] [--label <label>]...",
+	Short: "Push the current branch, then create its merge request or update the existing one",
+	Args:  cobra.NoArgs,
+	Run:   push,
+}
+
+func push(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := internal.CurrentProject()
+	branch := internal.CurrentBranch()
+	err := internal.GitPush(internal.CurrentRemote(branch))
+	if errors.Is(err, internal.ErrNonFastForward) {
+		utils.Err("Push rejected, the remote branch has diverged. Run git pull --rebase, then lab push again")
+	}
+	utils.Check(err)
+
+	client := internal.NewClient()
+	existing := internal.FindMergeRequest(client, project, branch)
+	if existing == nil {
+		createMergeRequest(cmd, nil)
+		return
+	}
+
+	opt := &gitlab.UpdateMergeRequestOptions{}
+	if title, _ := cmd.Flags().GetString("title"); title != "" {
+		opt.Title = gitlab.Ptr(title)
+	}
+	if cmd.Flags().Changed("description") {
+		description, _ := cmd.Flags().GetString("description")
+		opt.Description = gitlab.Ptr(description)
+	}
+	if target, _ := cmd.Flags().GetString("target"); target != "" {
+		opt.TargetBranch = gitlab.Ptr(target)
+	}
+	if labels, _ := cmd.Flags().GetStringArray("label"); len(labels) > 0 {
+		opt.AddLabels = (*gitlab.LabelOptions)(&labels)
+	}
+	draft, _ := cmd.Flags().GetBool("draft")
+	if *opt == (gitlab.UpdateMergeRequestOptions{}) && (!draft || existing.Draft) {
+		fmt.Printf("Merge request !%d of %s is up to date\n", existing.IID, branch)
+		fmt.Println(existing.WebURL)
+		return
+	}
+	isDraft := existing.Draft
+	if *opt != (gitlab.UpdateMergeRequestOptions{}) {
+		mr, err := internal.UpdateMR(client, project, existing.IID, opt)
+		utils.Check(err)
+		isDraft = mr.Draft
+	}
+	// a new title has no draft prefix, so it is added after the update
+	if draft && !isDraft {
+		utils.Check(internal.SetMRDraft(client, project, existing.IID, true))
+	}
+	utils.PrintlnWithColor(utils.ColorFg(fmt.Sprintf("Merge request !%d updated", existing.IID), internal.MainConfig.ThemeColor))
+	fmt.Println(existing.WebURL)
+}
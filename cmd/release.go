@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	releaseCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	releaseCreateCmd.Flags().String("tag", "", "the tag of the release, created from --ref if missing")
+	releaseCreateCmd.Flags().String("ref", "", "the branch or commit to create the missing tag from")
+	releaseCreateCmd.Flags().String("name", "", "release name, default the tag")
+	releaseCreateCmd.Flags().String("notes", "", "the file of the release notes, - read from stdin")
+	releaseCmd.AddCommand(releaseListCmd)
+	releaseCmd.AddCommand(releaseCreateCmd)
+	rootCmd.AddCommand(releaseCmd)
+}
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Manage the project releases",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var releaseListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the releases of the project",
+	Run:         listReleases,
+	Annotations: structuredOutput,
+}
+
+var releaseCreateCmd = &cobra.Command{
+	Use:   "create --tag <tag> [--name <name>] [--notes <path> | -] [--ref <ref>]",
+	Short: "Create a release, print its url",
+	Run:   createRelease,
+}
+
+func listReleases(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	releases := internal.ListReleases(internal.NewClient(), projectFlag(cmd))
+	if len(releases) == 0 {
+		utils.Err("no releases found")
+	}
+
+	printResult(releases, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "TAG\tNAME\tAUTHOR\tRELEASED")
+		for _, r := range releases {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", r.TagName, r.Name, r.Author.Username, formatTime(r.ReleasedAt))
+		}
+		_ = table.Flush()
+	})
+}
+
+func createRelease(cmd *cobra.Command, _ []string) {
+	tag, _ := cmd.Flags().GetString("tag")
+	if tag == "" {
+		utils.Err("release tag is required, use --tag")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	name, _ := cmd.Flags().GetString("name")
+	notes, _ := cmd.Flags().GetString("notes")
+	ref, _ := cmd.Flags().GetString("ref")
+
+	opts := &gitlab.CreateReleaseOptions{TagName: gitlab.Ptr(tag)}
+	if name != "" {
+		opts.Name = gitlab.Ptr(name)
+	}
+	if ref != "" {
+		opts.Ref = gitlab.Ptr(ref)
+	}
+	if notes != "" {
+		description, err := utils.ReadFileOrStdin(notes)
+		utils.Check(err)
+		opts.Description = gitlab.Ptr(string(description))
+	}
+	release, err := internal.CreateRelease(internal.NewClient(), project, opts)
+	utils.Check(err)
+
+	releaseURL := release.Links.Self
+	if releaseURL == "" {
+		releaseURL = fmt.Sprintf("%s/%s/-/releases/%s", internal.Config.BaseURL, project, url.PathEscape(tag))
+	}
+	fmt.Println(releaseURL)
+}
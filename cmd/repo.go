@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	repoBrowseCmd.Flags().String("rev", "", "open the path at the commit sha, default the current branch")
+	repoBrowseCmd.Flags().Bool("print", false, "print the url instead of open it in browser")
+	repoCmd.AddCommand(repoBrowseCmd)
+	repoTreeCmd.Flags().String("project", "", "project path with namespace, default the current repo")
+	repoTreeCmd.Flags().String("ref", "", "branch, tag or commit sha, default the default branch")
+	repoTreeCmd.Flags().String("path", "", "directory to list, default the repo root")
+	repoTreeCmd.Flags().BoolP("recursive", "r", false, "list the subdirectories too")
+	repoTreeCmd.Flags().Bool("only-files", false, "only list the files, with their path relative to the listed directory")
+	repoTreeCmd.Flags().Bool("only-dirs", false, "only list the directories")
+	repoTreeCmd.MarkFlagsMutuallyExclusive("only-files", "only-dirs")
+	repoCmd.AddCommand(repoTreeCmd)
+	rootCmd.AddCommand(repoCmd)
+}
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Browse the current repository",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var repoBrowseCmd = &cobra.Command{
+	Use:   "browse [<path>] [--rev <sha>]",
+	Short: "Open the repository, or a file or directory relative to the repo root, in browser",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   browseRepo,
+}
+
+var repoTreeCmd = &cobra.Command{
+	Use:         "tree [--ref <ref>] [--path <dir>] [--recursive]",
+	Short:       "Show the file tree of the project at the ref, like the tree command",
+	Args:        cobra.NoArgs,
+	Run:         listRepoTree,
+	Annotations: structuredOutput,
+}
+
+func browseRepo(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	root, err := internal.CurrentGitRepo()
+	if err != nil {
+		utils.Err("not a git repository")
+	}
+	root = strings.TrimSpace(root)
+	project := internal.CurrentProject()
+	ref, _ := cmd.Flags().GetString("rev")
+	if ref == "" {
+		ref = internal.CurrentBranch()
+	}
+
+	file := ""
+	if len(args) > 0 {
+		file = strings.Trim(path.Clean(filepath.ToSlash(args[0])), "/")
+		if file == "." {
+			file = ""
+		}
+	}
+	// gitlab shows the directories as tree and the files as blob
+	kind := "tree"
+	if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(file))); file != "" && err == nil && !info.IsDir() {
+		kind = "blob"
+	}
+	url := fmt.Sprintf("%s/%s/-/%s/%s", internal.Config.BaseURL, project, kind, ref)
+	if file != "" {
+		url += "/" + file
+	}
+	openOrPrint(cmd, url)
+}
+
+// treeEntry is a file or directory printed in the tree, name is relative to the parent entry
+type treeEntry struct {
+	name, path string
+	dir        bool
+}
+
+func listRepoTree(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	ref, _ := cmd.Flags().GetString("ref")
+	dir, _ := cmd.Flags().GetString("path")
+	dir = strings.Trim(path.Clean("/"+filepath.ToSlash(dir)), "/")
+	recursive, _ := cmd.Flags().GetBool("recursive")
+	onlyFiles, _ := cmd.Flags().GetBool("only-files")
+	onlyDirs, _ := cmd.Flags().GetBool("only-dirs")
+	nodes, err := internal.ListTree(internal.NewClient(), project, dir, ref, recursive)
+	utils.Check(err)
+
+	children := map[string][]treeEntry{}
+	dirs, files := 0, 0
+	listed := make([]*gitlab.TreeNode, 0, len(nodes))
+	for _, n := range nodes {
+		entry := treeEntry{name: n.Name, path: n.Path, dir: n.Type == "tree"}
+		if entry.dir && onlyFiles || !entry.dir && onlyDirs {
+			continue
+		}
+		listed = append(listed, n)
+		parent := path.Dir(n.Path)
+		if parent == "." {
+			parent = ""
+		}
+		// without the directories, the files of the subdirectories are listed in the root
+		if onlyFiles && parent != dir {
+			parent = dir
+			entry.name = strings.TrimPrefix(n.Path, dir+"/")
+		}
+		children[parent] = append(children[parent], entry)
+		if entry.dir {
+			dirs++
+		} else {
+			files++
+		}
+	}
+
+	printResult(listed, func() {
+		var b strings.Builder
+		b.WriteString(cmp.Or(dir, ".") + "\n")
+		writeTree(&b, children, dir, "")
+		fmt.Fprintf(&b, "\n%d directories, %d files\n", dirs, files)
+		fmt.Print(b.String())
+	})
+}
+
+// writeTree write the entries of the dir sorted by name, and the ones of its subdirectories indented
+func writeTree(b *strings.Builder, children map[string][]treeEntry, dir, indent string) {
+	entries := children[dir]
+	slices.SortFunc(entries, func(x, y treeEntry) int { return strings.Compare(x.name, y.name) })
+	dirColor := color.New(color.FgBlue, color.Bold)
+	for i, e := range entries {
+		branch, next := "├── ", "│   "
+		if i == len(entries)-1 {
+			branch, next = "└── ", "    "
+		}
+		name := e.name
+		if e.dir {
+			name = dirColor.Sprint(name)
+		}
+		b.WriteString(indent + branch + name + "\n")
+		if e.dir {
+			writeTree(b, children, e.path, indent+next)
+		}
+	}
+}
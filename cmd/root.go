@@ -1,30 +1,88 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
 )
 
+// profile is the config profile selected by --profile
+var profile string
+
+// output is the format selected by --output, table, json or yaml
+var output string
+
 func init() {
 	// init config after cobra command called
-	cobra.OnInitialize(internal.SetupConfig)
+	cobra.OnInitialize(internal.SetupConfig, checkOutput)
 	rootCmd.PersistentFlags().StringVar(&internal.ConfigPath, "config", "", "target config file (default is $HOME/.config/lab/config.toml)")
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "P", "", "gitlab profile in config file, default use [profiles.default] or [gitlab]")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format of the results, table, json or yaml")
 }
 
+// structuredOutput annotates the commands printing their result with printResult,
+// the other commands reject --output json or yaml instead of printing the table
+var structuredOutput = map[string]string{"output": "true"}
+
 var rootCmd = &cobra.Command{
 	Use:   "lab",
 	Short: "Lab is a cli tool, include some shortcut for gitlab",
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		if output != "" && output != "table" && cmd.Annotations["output"] == "" {
+			utils.Err(cmd.CommandPath(), "doesn't support --output", output)
+		}
+	},
 	Run: func(cmd *cobra.Command, _ []string) {
 		_ = cmd.Help()
 	},
 }
 
-// Execute is the root command
+// Execute is the root command, the context of the commands is canceled by ctrl-c
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// restore the default behavior after the first signal,
+	// so a second ctrl-c stops the commands not watching the context
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		os.Exit(1)
 	}
 }
+
+// projectFlag return the --project flag, default is the project of the current repo remote
+func projectFlag(cmd *cobra.Command) string {
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		project = internal.CurrentProject()
+	}
+	return project
+}
+
+func checkOutput() {
+	_, err := utils.NewPrinter(output, nil)
+	utils.Check(err)
+}
+
+// printResult print v in the --output format, render draws the table output
+func printResult(v any, render func()) {
+	printer, err := utils.NewPrinter(output, render)
+	utils.Check(err)
+	utils.Check(printer.Print(v))
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
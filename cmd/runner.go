@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	runnerCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	runnerListCmd.Flags().String("group", "", "list the runners of the group instead of the project")
+	runnerListCmd.Flags().Bool("all", false, "list all the runners of the instance, needs an admin token")
+	runnerListCmd.Flags().String("status", "", "filter by status, active or paused")
+	runnerListCmd.Flags().StringSlice("tag-list", nil, "only the runners with all the tags, comma separated")
+	for _, c := range []*cobra.Command{runnerPauseCmd, runnerResumeCmd} {
+		c.Flags().Bool("all", false, "all the runners of the instance with --tag, needs an admin token")
+		c.Flags().String("tag", "", "the tag of the runners with --all")
+		c.Flags().BoolP("yes", "y", false, "don't ask for confirmation with several runners")
+		runnerCmd.AddCommand(c)
+		c.MarkFlagsRequiredTogether("all", "tag")
+	}
+	runnerCmd.AddCommand(runnerListCmd)
+	runnerListCmd.MarkFlagsMutuallyExclusive("project", "group", "all")
+	rootCmd.AddCommand(runnerCmd)
+}
+
+var runnerCmd = &cobra.Command{
+	Use:   "runner",
+	Short: "Manage the ci runners",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var runnerListCmd = &cobra.Command{
+	Use:         "list [--project <ns/project> | --group <group> | --all] [--status active|paused] [--tag-list <t1,t2>]",
+	Short:       "List the runners available to the project or group, or all the runners",
+	Run:         listRunners,
+	Annotations: structuredOutput,
+}
+
+var runnerPauseCmd = &cobra.Command{
+	Use:   "pause <id> | --all --tag <tag>",
+	Short: "Pause the runner, or all the runners with the tag",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pauseRunners(cmd, args, true)
+	},
+}
+
+var runnerResumeCmd = &cobra.Command{
+	Use:   "resume <id> | --all --tag <tag>",
+	Short: "Resume the paused runner, or all the runners with the tag",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pauseRunners(cmd, args, false)
+	},
+}
+
+// runnerRow is a runner of lab runner list, the list api has no tags
+type runnerRow struct {
+	*gitlab.Runner
+	TagList []string `json:"tag_list"`
+}
+
+func listRunners(cmd *cobra.Command, _ []string) {
+	group, _ := cmd.Flags().GetString("group")
+	all, _ := cmd.Flags().GetBool("all")
+	status, _ := cmd.Flags().GetString("status")
+	tags, _ := cmd.Flags().GetStringSlice("tag-list")
+	if status != "" && status != "active" && status != "paused" {
+		utils.Err("invalid status", status, "use active or paused")
+	}
+	internal.Setup(profile)
+	client := internal.NewClient()
+
+	var runners []*gitlab.Runner
+	switch {
+	case all:
+		runners = internal.ListRunners(client, "all", nil, tags)
+	case group != "":
+		runners = internal.ListRunners(client, "group", group, tags)
+	default:
+		runners = internal.ListRunners(client, "project", projectFlag(cmd), tags)
+	}
+	filtered := runners[:0]
+	for _, r := range runners {
+		if status == "" || r.Paused == (status == "paused") {
+			filtered = append(filtered, r)
+		}
+	}
+	runnerTags := internal.RunnerTags(client, filtered)
+	rows := make([]runnerRow, 0, len(filtered))
+	for _, r := range filtered {
+		rows = append(rows, runnerRow{r, runnerTags[r.ID]})
+	}
+
+	printResult(rows, func() {
+		if len(rows) == 0 {
+			utils.Err("no runners found")
+		}
+		table := utils.NewTable()
+		fmt.Fprintln(table, "ID\tDESCRIPTION\tIP\tSTATUS\tTAGS")
+		for _, r := range rows {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", r.ID, orDash(r.Description), orDash(r.IPAddress), runnerStatus(r.Runner), orDash(strings.Join(r.TagList, ",")))
+		}
+		_ = table.Flush()
+	})
+}
+
+// runnerStatus return the connection status of the runner, like online, and paused if paused
+func runnerStatus(r *gitlab.Runner) string {
+	if r.Paused {
+		return utils.StatusColor("paused")
+	}
+	return utils.StatusColor(r.Status)
+}
+
+// pauseRunners pause the runner of args or the runners of --all --tag, or resume them if not pause
+func pauseRunners(cmd *cobra.Command, args []string, pause bool) {
+	all, _ := cmd.Flags().GetBool("all")
+	tag, _ := cmd.Flags().GetString("tag")
+	yes, _ := cmd.Flags().GetBool("yes")
+	if all == (len(args) > 0) {
+		utils.Err("runner id or --all --tag is required")
+	}
+	internal.Setup(profile)
+	client := internal.NewClient()
+
+	var runnerIDs []int
+	if all {
+		for _, r := range internal.ListRunners(client, "all", nil, []string{tag}) {
+			runnerIDs = append(runnerIDs, r.ID)
+		}
+		if len(runnerIDs) == 0 {
+			utils.Err("no runners with tag", tag)
+		}
+	} else {
+		runnerID, err := strconv.Atoi(args[0])
+		if err != nil {
+			utils.Err("invalid runner id", args[0])
+		}
+		runnerIDs = []int{runnerID}
+	}
+	action, done, update := "Resume", "resumed", internal.ResumeRunner
+	if pause {
+		action, done, update = "Pause", "paused", internal.PauseRunner
+	}
+	if len(runnerIDs) > 1 && !yes && !utils.Confirm(fmt.Sprintf("%s %d runners with tag %s?", action, len(runnerIDs), tag)) {
+		return
+	}
+	for _, id := range runnerIDs {
+		utils.Check(update(client, id))
+		fmt.Printf("Runner %d %s\n", id, done)
+	}
+}
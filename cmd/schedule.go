@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	scheduleCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	scheduleCreateCmd.Flags().String("cron", "", "the cron expression, like \"0 * * * *\"")
+	scheduleCreateCmd.Flags().String("ref", "", "branch or tag of the pipelines, default the default branch")
+	scheduleCreateCmd.Flags().String("description", "", "the schedule description")
+	scheduleCreateCmd.Flags().String("timezone", "", "timezone of the cron, like Europe/Zurich, default UTC")
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	scheduleCmd.AddCommand(scheduleCreateCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage the pipeline schedules",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Annotations: structuredOutput,
+	Use:         "list",
+	Short:       "List the pipeline schedules of the project",
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		schedules := internal.ListPipelineSchedules(internal.NewClient(), projectFlag(cmd))
+		printResult(schedules, func() {
+			if len(schedules) == 0 {
+				utils.Err("no pipeline schedules found")
+			}
+			table := utils.NewTable()
+			fmt.Fprintln(table, "ID\tDESCRIPTION\tREF\tCRON\tNEXT RUN\tACTIVE\tOWNER")
+			for _, s := range schedules {
+				owner := "-"
+				if s.Owner != nil {
+					owner = s.Owner.Username
+				}
+				fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%t\t%s\n", s.ID, s.Description, s.Ref, s.Cron, formatTime(s.NextRunAt), s.Active, owner)
+			}
+			_ = table.Flush()
+		})
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <id>",
+	Short: "Run the pipeline of the schedule now",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scheduleID, err := strconv.Atoi(args[0])
+		if err != nil {
+			utils.Err("invalid schedule id", args[0])
+		}
+		internal.Setup(profile)
+		utils.Check(internal.RunPipelineSchedule(internal.NewClient(), projectFlag(cmd), scheduleID))
+		fmt.Printf("Pipeline of schedule #%d started\n", scheduleID)
+	},
+}
+
+var scheduleCreateCmd = &cobra.Command{
+	Use:   "create --cron <cron> --description <desc> [--ref <ref>] [--timezone <tz>]",
+	Short: "Create a pipeline schedule of the project",
+	Args:  cobra.NoArgs,
+	Run:   createSchedule,
+}
+
+func createSchedule(cmd *cobra.Command, _ []string) {
+	cron, _ := cmd.Flags().GetString("cron")
+	description, _ := cmd.Flags().GetString("description")
+	ref, _ := cmd.Flags().GetString("ref")
+	timezone, _ := cmd.Flags().GetString("timezone")
+	if cron == "" {
+		utils.Err("cron is required, use --cron")
+	}
+	if description == "" {
+		utils.Err("description is required, use --description")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	client := internal.NewClient()
+	if ref == "" {
+		var err error
+		ref, err = internal.DefaultBranch(client, project)
+		utils.Check(err)
+	}
+	schedule, err := internal.CreatePipelineSchedule(client, project, description, ref, cron, timezone)
+	utils.Check(err)
+	utils.PrintlnWithColor(utils.ColorFg(fmt.Sprintf("Schedule #%d created", schedule.ID), internal.MainConfig.ThemeColor))
+	fmt.Println("next run at", formatTime(schedule.NextRunAt))
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	searchCmd.Flags().String("scope", "projects", "search scope, "+strings.Join(internal.SearchScopes, ", "))
+	searchCmd.Flags().String("project", "", "only search in the project, projects scope is not supported")
+	searchCmd.Flags().Bool("print", false, "print the url, default open it in browser")
+	rootCmd.AddCommand(searchCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query> [--scope <scope>] [--project <ns/project>] [--print]",
+	Short: "Search gitlab, fuzzy find a result and open it in browser",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   search,
+}
+
+func search(cmd *cobra.Command, args []string) {
+	internal.Setup(profile)
+	query := strings.Join(args, " ")
+	scope, _ := cmd.Flags().GetString("scope")
+	project, _ := cmd.Flags().GetString("project")
+	client := internal.NewClient()
+
+	var results any
+	if project != "" {
+		results = internal.ProjectSearch(client, project, query, scope)
+	} else {
+		results = internal.GlobalSearch(client, query, scope)
+	}
+	lines, urls := searchLines(client, results, project, query)
+	if len(lines) == 0 {
+		utils.Err("no results found")
+	}
+	line := internal.FuzzyFinder(lines)
+	// ctrl-c
+	if line == "" {
+		return
+	}
+	openOrPrint(cmd, urls[line])
+}
+
+// searchLines return the fuzzy finder lines of the search results, with the web url of each line
+func searchLines(client *gitlab.Client, results any, project, query string) ([]string, map[string]string) {
+	var lines []string
+	urls := map[string]string{}
+	add := func(line, webURL string) {
+		if _, ok := urls[line]; ok {
+			return
+		}
+		lines = append(lines, line)
+		urls[line] = webURL
+	}
+
+	switch rs := results.(type) {
+	case []*gitlab.Project:
+		for _, p := range rs {
+			add(p.PathWithNamespace, p.WebURL)
+		}
+	case []*gitlab.Blob:
+		// the global blob results only have the project id
+		paths := map[int]string{}
+		for _, b := range rs {
+			path := project
+			if path == "" {
+				if _, ok := paths[b.ProjectID]; !ok {
+					p, err := internal.GetProject(client, strconv.Itoa(b.ProjectID))
+					utils.Check(err)
+					paths[b.ProjectID] = p.PathWithNamespace
+				}
+				path = paths[b.ProjectID]
+			}
+			lineNumber, text := matchingLine(b, query)
+			webURL := fmt.Sprintf("%s/%s/-/blob/%s/%s#L%d", internal.Config.BaseURL, path, url.PathEscape(b.Ref), b.Path, lineNumber)
+			add(fmt.Sprintf("%s %s:%d: %s", path, b.Path, lineNumber, text), webURL)
+		}
+	case []*gitlab.Commit:
+		for _, c := range rs {
+			add(fmt.Sprintf("%s %s", c.ShortID, c.Title), c.WebURL)
+		}
+	case []*gitlab.Issue:
+		for _, i := range rs {
+			add(fmt.Sprintf("%s %s", issueReference(i), i.Title), i.WebURL)
+		}
+	case []*gitlab.MergeRequest:
+		for _, mr := range rs {
+			ref := fmt.Sprintf("!%d", mr.IID)
+			if mr.References != nil {
+				ref = mr.References.Full
+			}
+			add(fmt.Sprintf("%s %s", ref, mr.Title), mr.WebURL)
+		}
+	}
+	return lines, urls
+}
+
+func issueReference(issue *gitlab.Issue) string {
+	if issue.References != nil {
+		return issue.References.Full
+	}
+	return fmt.Sprintf("#%d", issue.IID)
+}
+
+// matchingLine return the first line of the blob excerpt containing the query, and its line number
+func matchingLine(blob *gitlab.Blob, query string) (int, string) {
+	lines := strings.Split(strings.TrimRight(blob.Data, "\n"), "\n")
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(line), strings.ToLower(query)) {
+			return blob.Startline + i, strings.TrimSpace(line)
+		}
+	}
+	return blob.Startline, strings.TrimSpace(lines[0])
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	snippetCmd.PersistentFlags().String("project", "", "project path with namespace, default the personal snippets")
+	snippetCreateCmd.Flags().StringP("title", "t", "", "snippet title, default the file name")
+	snippetCreateCmd.Flags().StringP("file", "f", "-", "the file of the snippet content, - read from stdin")
+	snippetCreateCmd.Flags().String("filename", "", "snippet file name, default the base name of --file")
+	snippetCreateCmd.Flags().Bool("private", false, "private visibility, the default")
+	snippetCreateCmd.Flags().Bool("internal", false, "internal visibility")
+	snippetCreateCmd.Flags().Bool("public", false, "public visibility")
+	snippetCreateCmd.MarkFlagsMutuallyExclusive("private", "internal", "public")
+	snippetListCmd.Flags().Bool("raw", false, "print all snippets as json, without the fuzzy finder")
+	snippetListCmd.Flags().Bool("print", false, "print the raw url, default copy it to the clipboard")
+	snippetCmd.AddCommand(snippetCreateCmd)
+	snippetCmd.AddCommand(snippetListCmd)
+	rootCmd.AddCommand(snippetCmd)
+}
+
+var snippetCmd = &cobra.Command{
+	Use:   "snippet",
+	Short: "Manage the gitlab snippets",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var snippetCreateCmd = &cobra.Command{
+	Use:   "create [-t <title>] [-f <path> | -] [--private | --internal | --public]",
+	Short: "Create a snippet from a file or stdin",
+	Run:   createSnippet,
+}
+
+var snippetListCmd = &cobra.Command{
+	Use:   "list [--raw] [--print]",
+	Short: "Fuzzy find the snippets, copy the raw url of the selected one",
+	Run:   listSnippets,
+}
+
+func createSnippet(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project, _ := cmd.Flags().GetString("project")
+	title, _ := cmd.Flags().GetString("title")
+	file, _ := cmd.Flags().GetString("file")
+	filename, _ := cmd.Flags().GetString("filename")
+
+	content, err := utils.ReadFileOrStdin(file)
+	utils.Check(err)
+	if filename == "" && file != "-" {
+		filename = filepath.Base(file)
+	}
+	if filename == "" {
+		filename = "snippet.txt"
+	}
+	if title == "" {
+		title = filename
+	}
+
+	visibility := "private"
+	for _, v := range []string{"internal", "public"} {
+		if ok, _ := cmd.Flags().GetBool(v); ok {
+			visibility = v
+		}
+	}
+
+	client := internal.NewClient()
+	var snippet *gitlab.Snippet
+	if project != "" {
+		snippet, err = internal.CreateProjectSnippet(client, project, title, filename, string(content), visibility)
+	} else {
+		snippet, err = internal.CreateSnippet(client, title, filename, string(content), visibility)
+	}
+	utils.Check(err)
+	fmt.Println(snippet.WebURL)
+}
+
+func listSnippets(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	project, _ := cmd.Flags().GetString("project")
+	raw, _ := cmd.Flags().GetBool("raw")
+	printURL, _ := cmd.Flags().GetBool("print")
+	client := internal.NewClient()
+
+	var snippets []*gitlab.Snippet
+	if project != "" {
+		snippets = internal.ListProjectSnippets(client, project)
+	} else {
+		snippets = internal.ListSnippets(client)
+	}
+	if raw {
+		buf, err := json.MarshalIndent(snippets, "", "  ")
+		utils.Check(err)
+		fmt.Println(string(buf))
+		return
+	}
+	if len(snippets) == 0 {
+		utils.Err("no snippets found")
+	}
+
+	lines := make([]string, 0, len(snippets))
+	for _, s := range snippets {
+		lines = append(lines, fmt.Sprintf("$%d %s", s.ID, s.Title))
+	}
+	// the finder may render the preview many times, fetch each content once
+	contents := map[int]string{}
+	index := internal.FuzzyPreviewFinder(lines, func(i int) string {
+		s := snippets[i]
+		content, ok := contents[s.ID]
+		if !ok {
+			var err error
+			content, err = internal.SnippetContent(client, project, s.ID)
+			if err != nil {
+				content = err.Error()
+			}
+			contents[s.ID] = content
+		}
+		author := fmt.Sprintf("%s (@%s)", s.Author.Name, s.Author.Username)
+		return preview(s.Title, author, nil, content)
+	})
+	// ctrl-c
+	if index < 0 {
+		return
+	}
+	rawURL := snippets[index].RawURL
+	if !printURL {
+		err := utils.CopyToClipboard(rawURL)
+		if err == nil {
+			fmt.Println("Copied", rawURL)
+			return
+		}
+		utils.Warn("copy to clipboard failed: ", err)
+	}
+	fmt.Println(rawURL)
+}
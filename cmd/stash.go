@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	stashCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	stashCmd.AddCommand(stashListCmd)
+	rootCmd.AddCommand(stashCmd)
+}
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Show the work in progress of the project, the draft merge requests",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Annotations: structuredOutput,
+	Use:         "list",
+	Short:       "List the open draft merge requests of the project",
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		mrs := internal.ListWIPMRs(internal.NewClient(), projectFlag(cmd))
+		printResult(mrs, func() {
+			if len(mrs) == 0 {
+				utils.Err("no draft merge requests found")
+			}
+			table := utils.NewTable()
+			fmt.Fprintln(table, "ID\tTITLE\tBRANCH\tUPDATED")
+			for _, mr := range mrs {
+				fmt.Fprintf(table, "!%d\t%s\t%s\t%s\n", mr.IID, mr.Title, mr.SourceBranch, formatTime(mr.UpdatedAt))
+			}
+			_ = table.Flush()
+		})
+	},
+}
@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"sort"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/ackerr/lab/internal"
@@ -13,31 +13,79 @@ import (
 
 func init() {
 	syncCmd.Flags().Bool("all", false, "sync all projects, default sync project if you are the membership")
+	syncCmd.Flags().Bool("force", false, "ignore the projects cache, fetch from gitlab")
+	syncCmd.Flags().Int("workers", 0, "the number of parallel requests, default use num_workers in config")
+	syncCmd.Flags().String("topic", "", "only sync the projects with the topic")
+	syncCmd.Flags().Int("min-stars", 0, "only sync the projects with at least n stars, default use min_stars in config")
+	syncCmd.Flags().Bool("archived", false, "sync the archived projects too, default use include_archived in config")
+	syncCmd.Flags().String("sort", "", "sort the projects by name, last_activity, stars or created, the order is kept per group, default sort by path")
+	syncCmd.Flags().Bool("dry-run", false, "print the added and removed projects, without writing the projects file")
 	rootCmd.AddCommand(syncCmd)
 }
 
 var syncCmd = &cobra.Command{
-	Use:   "sync [--all]",
+	Use:   "sync [--all] [--force] [--workers <n>] [--topic <topic>] [--min-stars <n>] [--archived] [--sort <sort>] [--dry-run]",
 	Short: "Sync gitlab projects",
-	Run: func(cmd *cobra.Command, _ []string) {
-		syncAll, _ := cmd.Flags().GetBool("all")
-		syncProjects(syncAll)
-	},
+	Run:   syncProjects,
 }
 
 // 同步项目, 顺便按字母排个序
-func syncProjects(syncAll bool) {
-	internal.Setup()
-	file, err := os.Create(internal.ProjectPath)
-	utils.Check(err)
+func syncProjects(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	opts := internal.SyncOptions{MinStars: internal.Config.MinStars, IncludeArchived: internal.Config.IncludeArchived}
+	opts.All, _ = cmd.Flags().GetBool("all")
+	opts.Force, _ = cmd.Flags().GetBool("force")
+	opts.Topic, _ = cmd.Flags().GetString("topic")
+	opts.Sort, _ = cmd.Flags().GetString("sort")
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	if _, ok := internal.ProjectSorts[opts.Sort]; opts.Sort != "" && !ok {
+		utils.Err("invalid sort", opts.Sort, "use name, last_activity, stars or created")
+	}
+	if cmd.Flags().Changed("min-stars") {
+		opts.MinStars, _ = cmd.Flags().GetInt("min-stars")
+	}
+	if cmd.Flags().Changed("archived") {
+		opts.IncludeArchived, _ = cmd.Flags().GetBool("archived")
+	}
+	if cmd.Flags().Changed("workers") {
+		internal.MainConfig.NumWorkers, _ = cmd.Flags().GetInt("workers")
+	}
 
-	defer file.Close()
-	ns := internal.Projects(syncAll)
-	sort.Strings(ns)
-	for _, n := range ns {
-		if n != "" {
-			fmt.Fprintln(file, n)
-		}
+	ns := internal.Projects(cmd.Context(), opts)
+	// keep the projects file of the last sync
+	if cmd.Context().Err() != nil {
+		utils.Err("\nsync canceled")
+	}
+	if opts.DryRun {
+		printProjectsDiff(ns)
+		return
+	}
+	// with --sort keep the order of gitlab
+	if opts.Sort == "" {
+		sort.Slice(ns, func(i, j int) bool { return ns[i].Path < ns[j].Path })
 	}
+	db, err := internal.OpenProjectDB()
+	utils.Check(err)
+	defer db.Close()
+	utils.Check(internal.SaveProjects(db, ns))
 	println("Done.")
 }
+
+// printProjectsDiff print the changes of the synced projects if the projects are synced
+func printProjectsDiff(projects []internal.ProjectInfo) {
+	db, err := internal.OpenProjectDB()
+	utils.Check(err)
+	defer db.Close()
+	paths := make([]string, 0, len(projects))
+	for _, p := range projects {
+		paths = append(paths, p.Path)
+	}
+	added, removed := internal.DiffProjectLists(internal.QueryProjects(db, internal.ProjectFilter{}), paths)
+	for _, p := range added {
+		fmt.Println(color.GreenString("+ " + p))
+	}
+	for _, p := range removed {
+		fmt.Println(color.RedString("- " + p))
+	}
+	fmt.Printf("%d added, %d removed\n", len(added), len(removed))
+}
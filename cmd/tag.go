@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	tagCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	tagListCmd.Flags().String("search", "", "only list the tags matching the search, ^ and $ anchor the name")
+	tagListCmd.Flags().String("sort", "date", "sort the tags by name or date")
+	tagListCmd.Flags().Bool("asc", false, "sort in ascending order, default the newest or the last name first")
+	tagListCmd.Flags().Int("limit", 20, "maximum number of tags per page")
+	tagListCmd.Flags().Int("page", 1, "the page to list")
+	tagCmd.AddCommand(tagListCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage the project tags",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:         "list [--search <q>] [--sort name|date] [--asc] [--limit <n>] [--page <n>]",
+	Short:       "List the tags with their messages and pipeline status",
+	Run:         listTags,
+	Annotations: structuredOutput,
+}
+
+func listTags(cmd *cobra.Command, _ []string) {
+	sortBy, _ := cmd.Flags().GetString("sort")
+	if sortBy != "name" && sortBy != "date" {
+		utils.Err("invalid sort", sortBy, "use name or date")
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+	if limit <= 0 || page <= 0 {
+		utils.Err("--limit and --page must be positive")
+	}
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	search, _ := cmd.Flags().GetString("search")
+	asc, _ := cmd.Flags().GetBool("asc")
+	client := internal.NewClient()
+
+	tags := internal.ListTags(client, project, search)
+	sortTags(tags, sortBy, !asc)
+	start := min((page-1)*limit, len(tags))
+	tags = tags[start:min(start+limit, len(tags))]
+	if len(tags) == 0 {
+		utils.Err("no tags found")
+	}
+
+	printResult(tags, func() {
+		statuses := internal.TagPipelines(client, project, tags)
+		table := utils.NewTable()
+		fmt.Fprintln(table, "TAG\tMESSAGE\tAUTHOR\tDATE\tPIPELINE")
+		for _, t := range tags {
+			author, date := "-", "-"
+			if t.Commit != nil {
+				author, date = t.Commit.AuthorName, formatTime(t.Commit.CommittedDate)
+			}
+			message, _, _ := strings.Cut(strings.TrimSpace(t.Message), "\n")
+			if message == "" {
+				message = "-"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", t.Name, message, author, date, orDash(statuses[t.Name]))
+		}
+		_ = table.Flush()
+	})
+}
+
+// sortTags sort the tags by name or by the date of their commit, descending if desc
+func sortTags(tags []*gitlab.Tag, sortBy string, desc bool) {
+	less := func(a, b *gitlab.Tag) bool {
+		if sortBy == "name" || a.Commit == nil || b.Commit == nil ||
+			a.Commit.CommittedDate == nil || b.Commit.CommittedDate == nil {
+			return a.Name < b.Name
+		}
+		return a.Commit.CommittedDate.Before(*b.Commit.CommittedDate)
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		if desc {
+			return less(tags[j], tags[i])
+		}
+		return less(tags[i], tags[j])
+	})
+}
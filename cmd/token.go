@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+// lowRateLimit is the remaining requests below which lab token info warns
+const lowRateLimit = 100
+
+func init() {
+	tokenCmd.AddCommand(tokenInfoCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Inspect the gitlab access token",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var tokenInfoCmd = &cobra.Command{
+	Use:         "info",
+	Short:       "Show the token name, scopes, expiry and the rate limit usage",
+	Run:         tokenInfo,
+	Annotations: structuredOutput,
+}
+
+// tokenReport is the --output json or yaml of lab token info, token is nil when unavailable
+type tokenReport struct {
+	Token     *gitlab.PersonalAccessToken `json:"token"`
+	RateLimit struct {
+		Limit     int        `json:"limit"`
+		Remaining int        `json:"remaining"`
+		ResetAt   *time.Time `json:"reset_at"`
+	} `json:"rate_limit"`
+}
+
+func tokenInfo(_ *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	client := internal.NewClient()
+
+	report := tokenReport{}
+	token, err := internal.GetTokenInfo(client)
+	if err == nil {
+		report.Token = token
+	}
+	limit, remaining, reset, rateErr := internal.GetRateLimit(client)
+	utils.Check(rateErr)
+	report.RateLimit.Limit, report.RateLimit.Remaining = limit, remaining
+	if reset > 0 {
+		resetAt := time.Unix(int64(reset), 0)
+		report.RateLimit.ResetAt = &resetAt
+	}
+
+	printResult(report, func() {
+		table := utils.NewTable()
+		if token != nil {
+			expires := "never"
+			if token.ExpiresAt != nil {
+				expires = token.ExpiresAt.String()
+			}
+			fmt.Fprintf(table, "Name\t%s\n", token.Name)
+			fmt.Fprintf(table, "Scopes\t%s\n", strings.Join(token.Scopes, ", "))
+			fmt.Fprintf(table, "Expires\t%s\n", expires)
+			fmt.Fprintf(table, "Last used\t%s\n", formatTime(token.LastUsedAt))
+		}
+		if limit == 0 {
+			fmt.Fprintln(table, "Rate limit\tnot reported by gitlab")
+		} else {
+			fmt.Fprintf(table, "Rate limit\t%d/%d remaining, reset at %s\n", remaining, limit, formatTime(report.RateLimit.ResetAt))
+		}
+		_ = table.Flush()
+
+		if err != nil {
+			utils.Warn("token metadata unavailable, oauth tokens and gitlab older than 15.5 don't support it: ", err)
+		}
+		if limit > 0 && remaining < lowRateLimit {
+			utils.Warn(fmt.Sprintf("Warning: only %d requests remaining until %s", remaining, formatTime(report.RateLimit.ResetAt)))
+		}
+	})
+}
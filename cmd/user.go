@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	userCmd.AddCommand(userInfoCmd)
+	userCmd.AddCommand(userSearchCmd)
+	rootCmd.AddCommand(userCmd)
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Look up the gitlab users",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var userInfoCmd = &cobra.Command{
+	Use:         "info <username>",
+	Short:       "Show the profile and the groups of the user",
+	Args:        cobra.ExactArgs(1),
+	Run:         userInfo,
+	Annotations: structuredOutput,
+}
+
+var userSearchCmd = &cobra.Command{
+	Use:         "search <query>",
+	Short:       "List the users matching the name, username or public email",
+	Args:        cobra.ExactArgs(1),
+	Run:         searchUsers,
+	Annotations: structuredOutput,
+}
+
+func userInfo(_ *cobra.Command, args []string) {
+	internal.Setup(profile)
+	client := internal.NewClient()
+	user, err := internal.GetUser(client, args[0])
+	utils.Check(err)
+	// the memberships api is only allowed for admins
+	groups, groupsErr := internal.UserGroups(client, user.ID)
+
+	result := struct {
+		*gitlab.User
+		Groups []*gitlab.UserMembership `json:"groups,omitempty"`
+	}{user, groups}
+	printResult(result, func() {
+		email := user.PublicEmail
+		if email == "" {
+			email = user.Email
+		}
+		fmt.Println(utils.ColorFg(user.Name+" (@"+user.Username+")", internal.MainConfig.ThemeColor))
+		table := utils.NewTable()
+		fmt.Fprintf(table, "email\t%s\n", orDash(email))
+		fmt.Fprintf(table, "bio\t%s\n", orDash(user.Bio))
+		fmt.Fprintf(table, "location\t%s\n", orDash(user.Location))
+		fmt.Fprintf(table, "website\t%s\n", orDash(user.WebsiteURL))
+		fmt.Fprintf(table, "member since\t%s\n", formatTime(user.CreatedAt))
+		fmt.Fprintf(table, "profile\t%s\n", user.WebURL)
+		for i, g := range groups {
+			label := ""
+			if i == 0 {
+				label = "groups"
+			}
+			fmt.Fprintf(table, "%s\t%s (%s)\n", label, g.SourceName, internal.AccessLevels[g.AccessLevel])
+		}
+		_ = table.Flush()
+		if groupsErr != nil {
+			utils.Warn("the group memberships need admin access: ", groupsErr)
+		}
+	})
+}
+
+func searchUsers(_ *cobra.Command, args []string) {
+	internal.Setup(profile)
+	users := internal.SearchUsers(internal.NewClient(), args[0])
+	if len(users) == 0 {
+		utils.Err("no users found")
+	}
+
+	printResult(users, func() {
+		table := utils.NewTable()
+		fmt.Fprintln(table, "USERNAME\tNAME\tSTATE")
+		for _, u := range users {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", u.Username, u.Name, u.State)
+		}
+		_ = table.Flush()
+	})
+}
+
+// orDash return - for the empty value
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
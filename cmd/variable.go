@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	variableCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	variableListCmd.Flags().String("group", "", "list the variables of the group instead of the project")
+	variableListCmd.Flags().Bool("show-values", false, "print the values, the hidden values are never returned by gitlab")
+	variableSetCmd.Flags().Bool("protected", false, "only export the variable to the protected branches and tags")
+	variableSetCmd.Flags().Bool("masked", false, "mask the value in the job logs")
+	variableSetCmd.Flags().String("type", "env_var", "variable type, env_var or file")
+	variableCmd.AddCommand(variableListCmd)
+	variableCmd.AddCommand(variableSetCmd)
+	variableCmd.AddCommand(variableDeleteCmd)
+	variableListCmd.MarkFlagsMutuallyExclusive("project", "group")
+	rootCmd.AddCommand(variableCmd)
+}
+
+var variableCmd = &cobra.Command{
+	Use:   "variable",
+	Short: "Manage the ci/cd variables",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var variableListCmd = &cobra.Command{
+	Use:         "list [--project <ns/project> | --group <group>] [--show-values]",
+	Short:       "List the ci/cd variables of the project or group",
+	Run:         listVariables,
+	Annotations: structuredOutput,
+}
+
+var variableSetCmd = &cobra.Command{
+	Use:   "set KEY=VALUE [--protected] [--masked] [--type env_var|file]",
+	Short: "Create or update a ci/cd variable of the project",
+	Args:  cobra.ExactArgs(1),
+	Run:   setVariable,
+}
+
+var variableDeleteCmd = &cobra.Command{
+	Use:   "delete KEY",
+	Short: "Delete a ci/cd variable of the project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		internal.Setup(profile)
+		project := projectFlag(cmd)
+		utils.Check(internal.DeleteProjectVariable(internal.NewClient(), project, args[0]))
+		fmt.Println("Variable", args[0], "deleted")
+	},
+}
+
+func listVariables(cmd *cobra.Command, _ []string) {
+	internal.Setup(profile)
+	group, _ := cmd.Flags().GetString("group")
+	showValues, _ := cmd.Flags().GetBool("show-values")
+	client := internal.NewClient()
+
+	var variables []*gitlab.ProjectVariable
+	target := group
+	if group != "" {
+		for _, v := range internal.ListGroupVariables(client, group) {
+			// the group and project variables have the same fields
+			pv := gitlab.ProjectVariable(*v)
+			variables = append(variables, &pv)
+		}
+	} else {
+		target = projectFlag(cmd)
+		variables = internal.ListProjectVariables(client, target)
+	}
+	if len(variables) == 0 {
+		utils.Err("no variables found")
+	}
+	if showValues && !utils.Confirm(fmt.Sprintf("Print the variable values of %s?", target)) {
+		showValues = false
+	}
+
+	if !showValues {
+		// never print the values in the json or yaml output without --show-values
+		for _, v := range variables {
+			v.Value = ""
+		}
+	}
+	printResult(variables, func() {
+		table := utils.NewTable()
+		header := "KEY\tTYPE\tPROTECTED\tMASKED\tENVIRONMENTS"
+		if showValues {
+			header += "\tVALUE"
+		}
+		fmt.Fprintln(table, header)
+		for _, v := range variables {
+			varType := "env var"
+			if v.VariableType == gitlab.FileVariableType {
+				varType = "file"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%t\t%t\t%s", v.Key, varType, v.Protected, v.Masked || v.Hidden, v.EnvironmentScope)
+			if showValues {
+				value := v.Value
+				if v.Hidden {
+					value = "[hidden]"
+				}
+				fmt.Fprintf(table, "\t%s", value)
+			}
+			fmt.Fprintln(table)
+		}
+		_ = table.Flush()
+	})
+}
+
+func setVariable(cmd *cobra.Command, args []string) {
+	key, value, ok := strings.Cut(args[0], "=")
+	if !ok || key == "" {
+		utils.Err("invalid variable", args[0], "use KEY=VALUE")
+	}
+	varType, _ := cmd.Flags().GetString("type")
+	if varType != string(gitlab.EnvVariableType) && varType != string(gitlab.FileVariableType) {
+		utils.Err("invalid type", varType, "use env_var or file")
+	}
+	protected, _ := cmd.Flags().GetBool("protected")
+	masked, _ := cmd.Flags().GetBool("masked")
+	if masked && !internal.Maskable(value) {
+		utils.Warn("the value may not be masked, gitlab requires a single line of at least 8 characters from the base64 alphabet, @, :, ., ~ and -")
+	}
+
+	internal.Setup(profile)
+	project := projectFlag(cmd)
+	utils.Check(internal.SetProjectVariable(internal.NewClient(), project, key, value, varType, protected, masked))
+	fmt.Println("Variable", key, "saved")
+}
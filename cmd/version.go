@@ -2,20 +2,86 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+// version and commit are set at build time, like
+// go build -ldflags "-X github.com/ackerr/lab/cmd.version=v0.4.3 -X github.com/ackerr/lab/cmd.commit=abc123"
+var (
+	version = "v0.4.3"
+	commit  = ""
 )
 
-const version = "v0.4.3"
+const clientGoPath = "gitlab.com/gitlab-org/api/client-go"
 
 var versionCmd = &cobra.Command{
-	Use:   "version",
+	Use:   "version [--check-update]",
 	Short: "Print the version number of Lab",
 	Run: func(cmd *cobra.Command, _ []string) {
-		fmt.Println("lab", version)
+		clientGo := "unknown"
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, dep := range info.Deps {
+				if dep.Path == clientGoPath {
+					clientGo = dep.Version
+				}
+			}
+			// go build embeds the vcs revision if the commit is not set by ldflags
+			for _, s := range info.Settings {
+				if s.Key == "vcs.revision" && commit == "" {
+					commit = s.Value[:min(len(s.Value), 7)]
+				}
+			}
+		}
+		if commit != "" {
+			fmt.Printf("lab %s (%s)\n", version, commit)
+		} else {
+			fmt.Println("lab", version)
+		}
+		fmt.Println(clientGoPath, clientGo)
+		fmt.Println(runtime.Version(), runtime.GOOS+"/"+runtime.GOARCH)
+
+		if checkUpdate, _ := cmd.Flags().GetBool("check-update"); !checkUpdate {
+			return
+		}
+		latest, err := internal.LatestRelease()
+		utils.Check(err)
+		if newerVersion(latest, version) {
+			fmt.Printf("lab %s is available, you have %s\n", latest, version)
+			fmt.Println("https://github.com/ackerr/lab/releases/latest")
+		} else {
+			fmt.Println("lab is up to date")
+		}
 	},
 }
 
 func init() {
+	versionCmd.Flags().Bool("check-update", false, "check if a newer lab release exists")
 	rootCmd.AddCommand(versionCmd)
 }
+
+// newerVersion return true if the version latest is newer than current, like v0.5.0 and v0.4.3
+func newerVersion(latest, current string) bool {
+	l := strings.Split(strings.TrimPrefix(latest, "v"), ".")
+	c := strings.Split(strings.TrimPrefix(current, "v"), ".")
+	for i := 0; i < max(len(l), len(c)); i++ {
+		var a, b int
+		if i < len(l) {
+			a, _ = strconv.Atoi(l[i])
+		}
+		if i < len(c) {
+			b, _ = strconv.Atoi(c[i])
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	webhookCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	webhookCreateCmd.Flags().String("url", "", "the url called by the webhook")
+	webhookCreateCmd.Flags().Bool("push-events", false, "trigger on the branch pushes, enabled by gitlab unless set to false")
+	webhookCreateCmd.Flags().Bool("tag-push-events", false, "trigger on the tag pushes")
+	webhookCreateCmd.Flags().Bool("mr-events", false, "trigger on the merge request events")
+	webhookCreateCmd.Flags().Bool("pipeline-events", false, "trigger on the pipeline status changes")
+	webhookCreateCmd.Flags().String("secret", "", "secret token sent in the X-Gitlab-Token header")
+	webhookCreateCmd.Flags().Bool("enable-ssl-verification", true, "verify the ssl certificate of the url")
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookCreateCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage the project webhooks",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Annotations: structuredOutput,
+	Use:         "list",
+	Short:       "List the webhooks of the project",
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		hooks := internal.ListWebhooks(internal.NewClient(), projectFlag(cmd))
+		if len(hooks) == 0 {
+			utils.Err("no webhooks found")
+		}
+
+		printResult(hooks, func() {
+			table := utils.NewTable()
+			fmt.Fprintln(table, "ID\tURL\tEVENTS\tSSL VERIFICATION")
+			for _, h := range hooks {
+				fmt.Fprintf(table, "%d\t%s\t%s\t%t\n", h.ID, h.URL, webhookEvents(h), h.EnableSSLVerification)
+			}
+			_ = table.Flush()
+		})
+	},
+}
+
+var webhookCreateCmd = &cobra.Command{
+	Use:   "create --url <url> [--push-events] [--tag-push-events] [--mr-events] [--pipeline-events] [--secret <token>]",
+	Short: "Create a webhook, print its id",
+	Run:   createWebhook,
+}
+
+func createWebhook(cmd *cobra.Command, _ []string) {
+	url, _ := cmd.Flags().GetString("url")
+	if url == "" {
+		utils.Err("webhook url is required, use --url")
+	}
+	opts := internal.WebhookOptions{}
+	opts.PushEvents = changedBool(cmd, "push-events")
+	opts.TagPushEvents = changedBool(cmd, "tag-push-events")
+	opts.MergeRequestsEvents = changedBool(cmd, "mr-events")
+	opts.PipelineEvents = changedBool(cmd, "pipeline-events")
+	opts.Secret, _ = cmd.Flags().GetString("secret")
+	opts.EnableSSLVerification, _ = cmd.Flags().GetBool("enable-ssl-verification")
+
+	internal.Setup(profile)
+	hook, err := internal.CreateWebhook(internal.NewClient(), projectFlag(cmd), url, opts)
+	utils.Check(err)
+	fmt.Println(hook.ID)
+}
+
+// webhookEvents return the enabled events of the hook, separated by comma
+func webhookEvents(h *gitlab.ProjectHook) string {
+	var events []string
+	for name, enabled := range map[string]bool{
+		"push":          h.PushEvents,
+		"tag_push":      h.TagPushEvents,
+		"merge_request": h.MergeRequestsEvents,
+		"pipeline":      h.PipelineEvents,
+		"issues":        h.IssuesEvents,
+		"note":          h.NoteEvents,
+		"job":           h.JobEvents,
+		"deployment":    h.DeploymentEvents,
+		"release":       h.ReleasesEvents,
+	} {
+		if enabled {
+			events = append(events, name)
+		}
+	}
+	if len(events) == 0 {
+		return "-"
+	}
+	sort.Strings(events)
+	return strings.Join(events, ",")
+}
+
+// changedBool return the bool flag if it is set, nil keeps the default of gitlab
+func changedBool(cmd *cobra.Command, name string) *bool {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetBool(name)
+	return &value
+}
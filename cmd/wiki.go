@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/internal"
+	"github.com/ackerr/lab/utils"
+)
+
+func init() {
+	wikiPageCmd.PersistentFlags().String("project", "", "project path with namespace, default the current repo")
+	wikiPageCmd.PersistentFlags().String("format", "markdown", "print the page as markdown or html")
+	wikiPageCmd.AddCommand(wikiListCmd)
+	wikiPageCmd.AddCommand(wikiGetCmd)
+	rootCmd.AddCommand(wikiPageCmd)
+}
+
+var wikiPageCmd = &cobra.Command{
+	Use:   "wiki",
+	Short: "Read the project wiki",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var wikiListCmd = &cobra.Command{
+	Use:   "list [--format markdown|html]",
+	Short: "Fuzzy find a wiki page, print its content",
+	Run: func(cmd *cobra.Command, _ []string) {
+		internal.Setup(profile)
+		project := projectFlag(cmd)
+		client := internal.NewClient()
+		pages := internal.ListWikiPages(client, project)
+		if len(pages) == 0 {
+			utils.Err("no wiki pages found")
+		}
+
+		lines := make([]string, 0, len(pages))
+		for _, page := range pages {
+			lines = append(lines, fmt.Sprintf("%s (%s)", page.Title, page.Slug))
+		}
+		index := internal.FuzzyPreviewFinder(lines, func(i int) string {
+			return preview(pages[i].Title, "", nil, pages[i].Content)
+		})
+		// ctrl-c
+		if index < 0 {
+			return
+		}
+		printWikiPage(cmd, client, project, pages[index])
+	},
+}
+
+var wikiGetCmd = &cobra.Command{
+	Use:   "get <slug> [--format markdown|html]",
+	Short: "Print the content of the wiki page",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		internal.Setup(profile)
+		project := projectFlag(cmd)
+		client := internal.NewClient()
+		page, err := internal.GetWikiPage(client, project, args[0])
+		utils.Check(err)
+		printWikiPage(cmd, client, project, page)
+	},
+}
+
+// printWikiPage print the page content, render it with the gitlab markdown api for --format html
+func printWikiPage(cmd *cobra.Command, client *gitlab.Client, project string, page *gitlab.Wiki) {
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "markdown":
+		fmt.Println(page.Content)
+	case "html":
+		html, err := internal.RenderMarkdown(client, project, page.Content)
+		utils.Check(err)
+		fmt.Println(html)
+	default:
+		utils.Err("invalid format", format, "use markdown or html")
+	}
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// DownloadArtifacts download the artifacts archive of the job, and extract it into dest.
+// The archive is streamed to a temp file, GetJobArtifacts of client-go buffers it in memory
+func DownloadArtifacts(client *gitlab.Client, pid any, jobID int, dest string) error {
+	u := fmt.Sprintf("projects/%s/jobs/%d/artifacts", gitlab.PathEscape(fmt.Sprint(pid)), jobID)
+	req, err := client.NewRequest(http.MethodGet, u, nil, nil)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", "lab-artifacts-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	s.Prefix = fmt.Sprintf("download artifacts of job #%d ", jobID)
+	s.Start()
+	// Do copies the response body to a io.Writer
+	_, err = client.Do(req, tmp)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return err
+	}
+	return unzip(tmp, info.Size(), dest)
+}
+
+func unzip(r io.ReaderAt, size int64, dest string) error {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	for _, f := range archive.File {
+		path := filepath.Join(dest, f.Name)
+		// refuse the entries outside of dest, like ../../etc/passwd
+		if !strings.HasPrefix(path, dest+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path in artifacts: %s", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			if err = os.MkdirAll(path, utils.DirPerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = extractFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), utils.DirPerm); err != nil {
+		return err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListAuditEvents return the audit events of the group, the latest first.
+// The audit events need gitlab premium and the owner role of the group
+func ListAuditEvents(client *gitlab.Client, groupID any, opts *gitlab.ListAuditEventsOptions) []*gitlab.AuditEvent {
+	opts.ListOptions = gitlab.ListOptions{PerPage: perPage, Page: 1}
+
+	var events []*gitlab.AuditEvent
+	for {
+		es, resp, err := client.AuditEvents.ListGroupAuditEvents(groupID, opts)
+		utils.Check(err)
+		events = append(events, es...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return events
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListBranches return all branches of the project
+func ListBranches(client *gitlab.Client, pid any) []*gitlab.Branch {
+	opt := &gitlab.ListBranchesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var branches []*gitlab.Branch
+	for {
+		bs, resp, err := client.Branches.ListBranches(pid, opt)
+		utils.Check(err)
+		branches = append(branches, bs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return branches
+}
+
+// DeleteBranch delete the remote branch of the project
+func DeleteBranch(client *gitlab.Client, pid any, branch string) error {
+	_, err := client.Branches.DeleteBranch(pid, branch)
+	return err
+}
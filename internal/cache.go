@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// cacheVersion must be bumped when the cache format changes,
+// the cache with a different version is ignored
+const cacheVersion = 2
+
+type projectCache struct {
+	Version   int           `json:"version"`
+	BaseURL   string        `json:"base_url"`
+	Key       string        `json:"key"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Projects  []ProjectInfo `json:"projects"`
+}
+
+func cachePath() string {
+	return filepath.Join(LabDir, "projects_cache.json")
+}
+
+// cacheTTL parse the cache_ttl of main config, zero means cache disabled
+func cacheTTL() time.Duration {
+	if MainConfig.CacheTTL == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(MainConfig.CacheTTL)
+	if err != nil {
+		utils.Err("invalid cache_ttl", MainConfig.CacheTTL, err)
+	}
+	return ttl
+}
+
+// readProjectCache return the cached projects, ok is false when the cache is missing,
+// broken, outdated or belongs to another gitlab or sync filters (key)
+func readProjectCache(ttl time.Duration, key string) (projects []ProjectInfo, ok bool) {
+	if ttl <= 0 {
+		return nil, false
+	}
+	buf, err := os.ReadFile(cachePath())
+	if err != nil {
+		return nil, false
+	}
+	cache := projectCache{}
+	if err = json.Unmarshal(buf, &cache); err != nil {
+		return nil, false
+	}
+	if cache.Version != cacheVersion || cache.BaseURL != Config.BaseURL || cache.Key != key {
+		return nil, false
+	}
+	if time.Since(cache.UpdatedAt) > ttl {
+		return nil, false
+	}
+	return cache.Projects, true
+}
+
+func writeProjectCache(projects []ProjectInfo, key string) error {
+	buf, err := json.Marshal(projectCache{
+		Version:   cacheVersion,
+		BaseURL:   Config.BaseURL,
+		Key:       key,
+		UpdatedAt: time.Now(),
+		Projects:  projects,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath(), buf, utils.FilePerm)
+}
@@ -0,0 +1,13 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// LintCIConfig validate the ci config content in the namespace of the project
+func LintCIConfig(client *gitlab.Client, pid any, content string) (*gitlab.ProjectLintResult, error) {
+	result, _, err := client.Validate.ProjectNamespaceLint(pid, &gitlab.ProjectNamespaceLintOptions{
+		Content: gitlab.Ptr(content),
+	})
+	return result, err
+}
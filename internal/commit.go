@@ -0,0 +1,56 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// maxCommits limit the commits listed, the history of a long living branch is huge
+const maxCommits = 1000
+
+// ListCommits return the commits of the ref, the newest first, at most maxCommits.
+// Empty ref means the default branch
+func ListCommits(client *gitlab.Client, pid any, ref string, opts *gitlab.ListCommitsOptions) []*gitlab.Commit {
+	if ref != "" {
+		opts.RefName = gitlab.Ptr(ref)
+	}
+	opts.ListOptions = gitlab.ListOptions{PerPage: perPage, Page: 1}
+
+	var commits []*gitlab.Commit
+	for {
+		cs, resp, err := client.Commits.ListCommits(pid, opts)
+		utils.Check(err)
+		commits = append(commits, cs...)
+		if resp.NextPage == 0 || len(commits) >= maxCommits {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return commits[:min(len(commits), maxCommits)]
+}
+
+// GetCommitDiff return the diffs of the files changed by the commit
+func GetCommitDiff(client *gitlab.Client, pid any, sha string) ([]*gitlab.Diff, error) {
+	opt := &gitlab.GetCommitDiffOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var diffs []*gitlab.Diff
+	for {
+		ds, resp, err := client.Commits.GetCommitDiff(pid, sha, opt)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, ds...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return diffs, nil
+}
+
+// GetCommit return the commit of the sha, a branch or tag name is the commit it points to
+func GetCommit(client *gitlab.Client, pid any, sha string) (*gitlab.Commit, error) {
+	commit, _, err := client.Commits.GetCommit(pid, sha, nil)
+	return commit, err
+}
@@ -2,8 +2,11 @@ package internal
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/a8m/envsubst"
@@ -19,11 +22,26 @@ base_url = "$GITLAB_BASE_URL"
 # gitlab access token
 token = "$GITLAB_TOKEN"
 
-# gitlab projects file
-# default $HOME/config/.lab/.projects
+# gitlab oauth application id, if set lab get the token by the oauth2
+# device authorization flow, and refresh it when expired.
+# token, refresh_token and token_expiry are written back by lab
+# default empty
+client_id = ""
+
+# the flat projects file of the older lab versions, lab sync stores the projects in
+# $HOME/.config/lab/projects.db now, the file is imported once, unless sync already ran
+# default $HOME/.config/lab/.projects
 projects = ""
 
-# If set, lab clone and lab cs will use this path as target path
+# If set, the projects are fetched from this url instead of gitlab, a file with one
+# project path per line, so a team can share a curated list without running lab sync.
+# The list is fetched again after cache_ttl, or after an hour without cache_ttl
+# default empty
+projects_url = ""
+
+# If set, lab clone and lab cs will use this path as target path, support ~ and $HOME.
+# The projects are cloned into <codespace>/<host>/<namespace>/<project>, lab cs also lists
+# the synced projects not cloned yet, lab cs --clone clones the selected one
 # default empty
 codespace = ""
 
@@ -35,6 +53,14 @@ name = ""
 # default empty
 email = ""
 
+# lab sync only keep the projects with at least min_stars stars
+# default 0
+min_stars = 0
+
+# If set true, lab sync keeps the archived projects too, use lab sync --archived once
+# default false
+include_archived = false
+
 [main]
 # If set 1, it will use fzf as fuzzy finder, default use go-fuzzyfinder
 # default 0
@@ -42,10 +68,32 @@ fzf = 0
 
 # lab clone extra custom git clone config
 # example clone_opts="--origin ackerr --branch fix"
+# clone_opts="--depth 1" makes all clones shallow, like lab clone --depth 1
 # default empty
 clone_opts = ""
+
+# lab sync reuse the projects fetched in this duration, like "24h"
+# use lab sync --force to ignore it
+# default empty, always fetch from gitlab
+cache_ttl = ""
+
+# the number of parallel gitlab requests in lab sync
+# default 5
+num_workers = 5
+
+# Extra gitlab instances, select one with lab --profile <name>.
+# Each profile accepts the same keys as [gitlab], a profile named
+# "default" is used instead of [gitlab] when --profile is omitted.
+# [profiles.work]
+# base_url = "https://gitlab.example.com"
+# token = "$GITLAB_WORK_TOKEN"
+# codespace = "~/work"
+# name = ""
+# email = ""
 `)
 
+const defaultThemeColor = "79"
+
 var (
 	// Config global gitlab config
 	Config      *gitlabConfig
@@ -53,6 +101,9 @@ var (
 	LabDir      string
 	ConfigPath  string
 	ProjectPath string
+
+	// configSection is the section of the selected profile
+	configSection string
 )
 
 func SetupConfig() {
@@ -76,12 +127,18 @@ func SetupConfig() {
 }
 
 type gitlabConfig struct {
-	BaseURL   string `mapstructure:"base_url"`
-	Token     string `mapstructure:"token"`
-	Codespace string `mapstructure:"codespace"`
-	Name      string `mapstructure:"name"`
-	Email     string `mapstructure:"email"`
-	Projects  string `mapstructure:"projects"`
+	BaseURL         string `mapstructure:"base_url"`
+	Token           string `mapstructure:"token"`
+	ClientID        string `mapstructure:"client_id"`
+	RefreshToken    string `mapstructure:"refresh_token"`
+	TokenExpiry     string `mapstructure:"token_expiry"`
+	Codespace       string `mapstructure:"codespace"`
+	Name            string `mapstructure:"name"`
+	Email           string `mapstructure:"email"`
+	Projects        string `mapstructure:"projects"`
+	ProjectsURL     string `mapstructure:"projects_url"`
+	MinStars        int    `mapstructure:"min_stars"`
+	IncludeArchived bool   `mapstructure:"include_archived"`
 }
 
 type mainConfig struct {
@@ -89,29 +146,36 @@ type mainConfig struct {
 	CloneOpts      string `mapstructure:"clone_opts"`
 	TailLineNumber int64  `mapstructure:"tail_line_number"`
 	FZF            bool   `mapstructure:"fzf"`
+	CacheTTL       string `mapstructure:"cache_ttl"`
+	NumWorkers     int    `mapstructure:"num_workers"`
 }
 
-func Setup() {
+// Setup load the gitlab config of the profile, and the main config.
+// If profile is empty, use [profiles.default] or the [gitlab] section
+func Setup(profile string) {
 	// init main config
 	MainConfig = &mainConfig{}
-	viper.SetDefault("main.theme_color", "79")
+	viper.SetDefault("main.theme_color", defaultThemeColor)
 	err := viper.Sub("main").Unmarshal(MainConfig)
 	utils.Check(err)
 	if len(MainConfig.ThemeColor) == 0 {
-		MainConfig.ThemeColor = "79"
+		MainConfig.ThemeColor = defaultThemeColor
 	}
 	if MainConfig.TailLineNumber == 0 {
 		MainConfig.TailLineNumber = 20
 	}
+	if MainConfig.NumWorkers <= 0 {
+		MainConfig.NumWorkers = 5
+	}
 
 	// init gitlab config
+	configSection = ProfileSection(profile)
 	Config = &gitlabConfig{}
-	err = viper.Sub("gitlab").Unmarshal(Config)
+	err = viper.Sub(configSection).Unmarshal(Config)
+	utils.Check(err)
+	// profile can override the main config too
+	err = viper.Sub(configSection).Unmarshal(MainConfig)
 	utils.Check(err)
-
-	if len(Config.Token) == 0 {
-		utils.Err("set Gitlab token first, use `lab config`")
-	}
 
 	baseURL := Config.BaseURL
 	if len(baseURL) == 0 {
@@ -122,18 +186,141 @@ func Setup() {
 	}
 	Config.BaseURL = strings.TrimSuffix(baseURL, "/")
 
-	home, err := os.UserHomeDir()
-	utils.Check(err)
-	codespace := Config.Codespace
-	if strings.HasPrefix(codespace, "~") {
-		codespace = filepath.Join(home, codespace[1:])
+	if tokenExpired() {
+		RefreshToken()
 	}
-	if strings.HasSuffix(codespace, string(os.PathSeparator)) {
-		codespace = codespace[:len(codespace)-len(string(os.PathSeparator))]
+	if len(Config.Token) == 0 {
+		utils.Err("set Gitlab token first, use `lab config`")
 	}
-	Config.Codespace = codespace
+
+	home, err := os.UserHomeDir()
+	utils.Check(err)
+	Config.Codespace = expandCodespace(Config.Codespace, home)
 	if Config.Projects == "" {
 		Config.Projects = filepath.Join(LabDir, ".projects")
 	}
 	ProjectPath = Config.Projects
 }
+
+// expandCodespace expand the leading ~ of the codespace, the environment variables like $HOME
+// are already expanded by envsubst when the config is read, so only ~ and ~/ are handled here.
+// The path is cleaned, it never ends with a separator
+func expandCodespace(codespace, home string) string {
+	if codespace == "" {
+		return ""
+	}
+	if codespace == "~" || strings.HasPrefix(codespace, "~"+string(os.PathSeparator)) {
+		codespace = filepath.Join(home, codespace[1:])
+	}
+	return filepath.Clean(codespace)
+}
+
+// ReadConfigValues return the raw values of the section in the config file,
+// the environment variables are not expanded, so they are kept when saving back
+func ReadConfigValues(section string) (map[string]string, error) {
+	buf, err := os.ReadFile(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	header := "[" + section + "]"
+	in := false
+	for _, line := range strings.Split(string(buf), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			in = trimmed == header
+			continue
+		}
+		k, v, ok := strings.Cut(trimmed, "=")
+		if !in || !ok || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if unquoted, err := strconv.Unquote(v); err == nil {
+			v = unquoted
+		}
+		values[strings.TrimSpace(k)] = v
+	}
+	return values, nil
+}
+
+// SaveConfig write the values to the section of the config file, strings are quoted, other values not.
+// The comments and other lines are kept untouched
+func SaveConfig(section string, values map[string]any) error {
+	buf, err := os.ReadFile(ConfigPath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(buf), "\n")
+	header := "[" + section + "]"
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == header {
+			start = i
+			continue
+		}
+		if start >= 0 && strings.HasPrefix(trimmed, "[") {
+			end = i
+			break
+		}
+	}
+	if start < 0 {
+		// keep the trailing newline of the file
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, "", header, "")
+		start, end = len(lines)-2, len(lines)-1
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		line := fmt.Sprintf("%s = %v", key, values[key])
+		if v, ok := values[key].(string); ok {
+			line = fmt.Sprintf("%s = %q", key, v)
+		}
+		found := false
+		for i := start + 1; i < end; i++ {
+			k, _, ok := strings.Cut(lines[i], "=")
+			if ok && strings.TrimSpace(k) == key {
+				lines[i] = line
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		// append after the last not empty line of the section
+		pos := end
+		for pos > start+1 && strings.TrimSpace(lines[pos-1]) == "" {
+			pos--
+		}
+		lines = append(lines[:pos], append([]string{line}, lines[pos:]...)...)
+		end++
+	}
+	return os.WriteFile(ConfigPath, []byte(strings.Join(lines, "\n")), utils.FilePerm)
+}
+
+// ProfileSection return the config section of the profile
+func ProfileSection(profile string) string {
+	if profile == "" {
+		if viper.Sub("profiles.default") != nil {
+			return "profiles.default"
+		}
+		if viper.Sub("gitlab") == nil {
+			utils.Err("[gitlab] section not found in", ConfigPath)
+		}
+		return "gitlab"
+	}
+	section := "profiles." + profile
+	if viper.Sub(section) == nil {
+		utils.Err("profile", profile, "not found in", ConfigPath)
+	}
+	return section
+}
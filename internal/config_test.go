@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/a8m/envsubst"
+)
+
+func TestExpandCodespace(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "home")
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		codespace string
+		want      string
+	}{
+		{"", ""},
+		{"$HOME/code", filepath.Join(home, "code")},
+		{"~", home},
+		{"~/x", filepath.Join(home, "x")},
+		{"~/x/", filepath.Join(home, "x")},
+		{"/srv/code/", "/srv/code"},
+		{"~user/x", "~user/x"},
+	}
+	for _, tt := range tests {
+		// the config is read with envsubst, like ReadConfig
+		codespace, err := envsubst.String(tt.codespace)
+		if err != nil {
+			t.Fatalf("envsubst %q: %v", tt.codespace, err)
+		}
+		if got := expandCodespace(codespace, home); got != tt.want {
+			t.Errorf("expandCodespace(%q) = %q, want %q", tt.codespace, got, tt.want)
+		}
+	}
+}
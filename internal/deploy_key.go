@@ -0,0 +1,34 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListDeployKeys return the deploy keys enabled for the project
+func ListDeployKeys(client *gitlab.Client, pid any) []*gitlab.ProjectDeployKey {
+	opt := &gitlab.ListProjectDeployKeysOptions{PerPage: perPage, Page: 1}
+
+	var keys []*gitlab.ProjectDeployKey
+	for {
+		ks, resp, err := client.DeployKeys.ListProjectDeployKeys(pid, opt)
+		utils.Check(err)
+		keys = append(keys, ks...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return keys
+}
+
+// AddDeployKey add the public ssh key to the project, canPush grant the write access
+func AddDeployKey(client *gitlab.Client, pid any, title, key string, canPush bool) error {
+	_, _, err := client.DeployKeys.AddDeployKey(pid, &gitlab.AddDeployKeyOptions{
+		Title:   gitlab.Ptr(title),
+		Key:     gitlab.Ptr(key),
+		CanPush: gitlab.Ptr(canPush),
+	})
+	return err
+}
@@ -0,0 +1,27 @@
+package internal
+
+import "sort"
+
+// DiffProjectLists return the projects in new but not in old, and the ones in old but not in new,
+// both sorted
+func DiffProjectLists(old, new []string) (added, removed []string) {
+	oldSet := make(map[string]bool, len(old))
+	for _, p := range old {
+		oldSet[p] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, p := range new {
+		newSet[p] = true
+		if !oldSet[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range old {
+		if !newSet[p] {
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffProjectLists(t *testing.T) {
+	tests := []struct {
+		name           string
+		old, new       []string
+		added, removed []string
+	}{
+		{"added", []string{"g/a"}, []string{"g/c", "g/a", "g/b"}, []string{"g/b", "g/c"}, nil},
+		{"removed", []string{"g/b", "g/a", "g/c"}, []string{"g/a"}, nil, []string{"g/b", "g/c"}},
+		{"added and removed", []string{"g/a", "g/b"}, []string{"g/b", "g/c"}, []string{"g/c"}, []string{"g/a"}},
+		{"unchanged", []string{"g/a", "g/b"}, []string{"g/b", "g/a"}, nil, nil},
+		{"empty old", nil, []string{"g/a"}, []string{"g/a"}, nil},
+		{"empty new", []string{"g/a"}, nil, nil, []string{"g/a"}},
+		{"both empty", nil, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := DiffProjectLists(tt.old, tt.new)
+			if !slices.Equal(added, tt.added) {
+				t.Errorf("added = %v, want %v", added, tt.added)
+			}
+			if !slices.Equal(removed, tt.removed) {
+				t.Errorf("removed = %v, want %v", removed, tt.removed)
+			}
+		})
+	}
+}
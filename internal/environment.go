@@ -0,0 +1,31 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListEnvironments return the deployment environments of the project,
+// the list api doesn't include the last deployment, use GetEnvironment for it
+func ListEnvironments(client *gitlab.Client, pid any) []*gitlab.Environment {
+	opt := &gitlab.ListEnvironmentsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var environments []*gitlab.Environment
+	for {
+		es, resp, err := client.Environments.ListEnvironments(pid, opt)
+		utils.Check(err)
+		environments = append(environments, es...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return environments
+}
+
+// GetEnvironment return the environment with its last deployment
+func GetEnvironment(client *gitlab.Client, pid any, environmentID int) (*gitlab.Environment, error) {
+	environment, _, err := client.Environments.GetEnvironment(pid, environmentID)
+	return environment, err
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"encoding/base64"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// BlameFile return the blame ranges of the file at the ref, each range is the lines of a commit
+func BlameFile(client *gitlab.Client, pid any, ref, filePath string) ([]*gitlab.FileBlameRange, error) {
+	blame, _, err := client.RepositoryFiles.GetFileBlame(pid, filePath, &gitlab.GetFileBlameOptions{Ref: gitlab.Ptr(ref)})
+	return blame, err
+}
+
+// CommitMergeRequest return the merge request which introduced the commit, the merged one
+// if there are several, nil if the commit wasn't merged by a merge request
+func CommitMergeRequest(client *gitlab.Client, pid any, sha string) (*gitlab.BasicMergeRequest, error) {
+	mrs, _, err := client.Commits.ListMergeRequestsByCommit(pid, sha)
+	if err != nil || len(mrs) == 0 {
+		return nil, err
+	}
+	for _, mr := range mrs {
+		if mr.State == "merged" {
+			return mr, nil
+		}
+	}
+	return mrs[0], nil
+}
+
+// GetFile return the content of the file at the ref
+func GetFile(client *gitlab.Client, pid any, filePath, ref string) ([]byte, error) {
+	file, _, err := client.RepositoryFiles.GetFile(pid, filePath, &gitlab.GetFileOptions{Ref: gitlab.Ptr(ref)})
+	if err != nil {
+		return nil, err
+	}
+	if file.Encoding != "base64" {
+		return []byte(file.Content), nil
+	}
+	return base64.StdEncoding.DecodeString(file.Content)
+}
+
+// ListTree return the files and directories under the path at the ref, of all subdirectories if recursive
+func ListTree(client *gitlab.Client, pid any, path, ref string, recursive bool) ([]*gitlab.TreeNode, error) {
+	opt := &gitlab.ListTreeOptions{
+		ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1},
+		Recursive:   gitlab.Ptr(recursive),
+	}
+	if path != "" {
+		opt.Path = gitlab.Ptr(path)
+	}
+	if ref != "" {
+		opt.Ref = gitlab.Ptr(ref)
+	}
+
+	var nodes []*gitlab.TreeNode
+	for {
+		ns, resp, err := client.Repositories.ListTree(pid, opt)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, ns...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return nodes, nil
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// FormField a labeled text field of the form
+type FormField struct {
+	Label  string
+	Value  string
+	Secret bool
+}
+
+type formModel struct {
+	title    string
+	labels   []string
+	inputs   []textinput.Model
+	focus    int
+	validate func(values []string) error
+	err      error
+	saved    bool
+}
+
+// Form show the fields in the terminal, enter/tab move to the next field, shift+tab to the previous one,
+// ctrl+s save and esc/ctrl+c cancel. validate is called before saving, the form stays open on error.
+// It returns the values in the order of fields, ok is false if canceled
+func Form(title string, fields []FormField, validate func(values []string) error) (values []string, ok bool, err error) {
+	m := &formModel{title: title, validate: validate}
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.Label))
+	}
+	for i, f := range fields {
+		input := textinput.New()
+		input.SetValue(f.Value)
+		input.Prompt = ""
+		if f.Secret {
+			input.EchoMode = textinput.EchoPassword
+		}
+		if i == 0 {
+			input.Focus()
+		}
+		m.labels = append(m.labels, f.Label+strings.Repeat(" ", width-len(f.Label)))
+		m.inputs = append(m.inputs, input)
+	}
+
+	if _, err = tea.NewProgram(m).Run(); err != nil {
+		return nil, false, err
+	}
+	if !m.saved {
+		return nil, false, nil
+	}
+	return m.values(), true, nil
+}
+
+func (m *formModel) values() []string {
+	values := make([]string, 0, len(m.inputs))
+	for _, input := range m.inputs {
+		values = append(values, strings.TrimSpace(input.Value()))
+	}
+	return values
+}
+
+func (m *formModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "ctrl+s":
+			if m.validate != nil {
+				if m.err = m.validate(m.values()); m.err != nil {
+					return m, nil
+				}
+			}
+			m.saved = true
+			return m, tea.Quit
+		case "enter", "tab", "down":
+			return m, m.move(1)
+		case "shift+tab", "up":
+			return m, m.move(-1)
+		}
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// move the focus to the next (1) or previous (-1) field
+func (m *formModel) move(step int) tea.Cmd {
+	m.inputs[m.focus].Blur()
+	m.focus = (m.focus + step + len(m.inputs)) % len(m.inputs)
+	return m.inputs[m.focus].Focus()
+}
+
+func (m *formModel) View() string {
+	color := defaultThemeColor
+	if MainConfig != nil {
+		color = MainConfig.ThemeColor
+	}
+	b := strings.Builder{}
+	b.WriteString(utils.ColorFg(m.title, color) + "\n\n")
+	for i, input := range m.inputs {
+		label := m.labels[i]
+		if i == m.focus {
+			label = utils.ColorFg(label, color)
+		}
+		b.WriteString(label + "  " + input.View() + "\n")
+	}
+	if m.err != nil {
+		b.WriteString("\n" + utils.ColorFg(m.err.Error(), "#F08080") + "\n")
+	}
+	b.WriteString("\nenter/tab next, shift+tab previous, ctrl+s save, esc cancel\n")
+	return b.String()
+}
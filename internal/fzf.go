@@ -1,33 +1,22 @@
 package internal
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/kballard/go-shellquote"
 	"github.com/ktr0731/go-fuzzyfinder"
 
 	"github.com/ackerr/lab/utils"
 )
 
-// FuzzyLine : fuzzy finder file line
-func FuzzyLine(filePath string) string {
-	lines, err := utils.ReadLines(filePath)
-	utils.Check(err)
-	filtered := FuzzyFinder(lines)
-	return filtered
-}
-
-// FuzzyLine : fuzzy finder file lines
-func FuzzyLines(filePath string) []string {
-	lines, err := utils.ReadLines(filePath)
-	utils.Check(err)
-	filtered := FuzzyMultiFinder(lines)
-	return filtered
-}
-
 // FuzzyFinder : fuzzy finder content
 func FuzzyFinder(lines []string) (filtered string) {
 	if checkFZF() {
@@ -77,6 +66,83 @@ func FuzzyMultiFinder(lines []string) (filtered []string) {
 	return
 }
 
+// FuzzyPreviewFinder : fuzzy finder content with a preview window,
+// return the selected index, -1 if canceled
+func FuzzyPreviewFinder(lines []string, preview func(i int) string) int {
+	if checkFZF() {
+		// fzf preview run a shell command, it asks this process for the preview of
+		// the line through a unix socket, so the preview is only rendered when shown
+		dir, err := os.MkdirTemp("", "lab-preview")
+		utils.Check(err)
+		defer os.RemoveAll(dir)
+		socket := filepath.Join(dir, "preview.sock")
+		listener, err := net.Listen("unix", socket)
+		utils.Check(err)
+		defer listener.Close()
+		go servePreviews(listener, len(lines), preview)
+		exe, err := os.Executable()
+		utils.Check(err)
+		command := shellquote.Join("fzf", "--ansi", "--delimiter", "\t", "--with-nth", "2..",
+			"--preview", shellquote.Join(exe, PreviewCommand, socket)+" {1}")
+		filters := withFilter(command, func(in io.WriteCloser) {
+			for i, line := range lines {
+				fmt.Fprintf(in, "%d\t%s\n", i, line)
+			}
+		})
+		if len(filters) == 0 || filters[0] == "" {
+			return -1
+		}
+		index, err := strconv.Atoi(strings.SplitN(filters[0], "\t", 2)[0])
+		utils.Check(err)
+		return index
+	}
+	index, err := fuzzyfinder.Find(lines, func(i int) string {
+		return lines[i]
+	}, fuzzyfinder.WithPreviewWindow(func(i, _, _ int) string {
+		if i == -1 {
+			return ""
+		}
+		return preview(i)
+	}))
+	if err == fuzzyfinder.ErrAbort {
+		return -1
+	}
+	utils.Check(err)
+	return index
+}
+
+// PreviewCommand is the hidden lab command run by the fzf preview, see PrintPreview
+const PreviewCommand = "fzf-preview"
+
+// servePreviews answer the preview of the line index read from each connection, one at a time
+func servePreviews(listener net.Listener, count int, preview func(i int) string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		if i, err := strconv.Atoi(strings.TrimSpace(line)); err == nil && i >= 0 && i < count {
+			_, _ = io.WriteString(conn, preview(i))
+		}
+		conn.Close()
+	}
+}
+
+// PrintPreview print the preview of the line index served by FuzzyPreviewFinder on the socket
+func PrintPreview(socket, index string) error {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err = fmt.Fprintln(conn, index); err != nil {
+		return err
+	}
+	_, err = io.Copy(os.Stdout, conn)
+	return err
+}
+
 func checkFZF() bool {
 	if !MainConfig.FZF {
 		return false
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -16,15 +19,41 @@ func GitCommand(args ...string) (string, error) {
 	return string(output), err
 }
 
-// Clone git clone the gitlab project
-func Clone(gitURL, path string) error {
-	args := []string{"clone", gitURL, path}
-	extra := strings.Split(MainConfig.CloneOpts, " ")
-	for _, a := range extra {
-		if a != "" {
-			args = append(args, a)
-		}
+// GitRun print the git command, then run it with the stdout and stderr
+func GitRun(args ...string) error {
+	utils.PrintlnWithColor(utils.ColorFg("git "+strings.Join(args, " "), MainConfig.ThemeColor))
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// ErrNonFastForward the push is rejected, the remote branch has commits missing locally
+var ErrNonFastForward = errors.New("the remote branch has diverged")
+
+// GitPush print the git command, then push HEAD to the branch of the same name of the remote.
+// It returns ErrNonFastForward if git rejects the push
+func GitPush(remote string) error {
+	args := []string{"push", remote, "HEAD"}
+	utils.PrintlnWithColor(utils.ColorFg("git "+strings.Join(args, " "), MainConfig.ThemeColor))
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	err := cmd.Run()
+	if err != nil && (strings.Contains(stderr.String(), "non-fast-forward") || strings.Contains(stderr.String(), "fetch first")) {
+		return ErrNonFastForward
 	}
+	return err
+}
+
+// Clone git clone the gitlab project, opts are added to the clone_opts of the config.
+// The options are put before the url and the path
+func Clone(gitURL, path string, opts ...string) error {
+	args := []string{"clone"}
+	args = append(args, strings.Fields(MainConfig.CloneOpts)...)
+	args = append(args, opts...)
+	args = append(args, gitURL, path)
 	cmd := exec.Command("git", args...)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
@@ -34,12 +63,8 @@ func Clone(gitURL, path string) error {
 		_ = os.Remove(path)
 		return err
 	}
-	if len(Config.Email) > 0 {
-		_ = SetGitConfig("email", Config.Email, path)
-	}
-	if len(Config.Name) > 0 {
-		_ = SetGitConfig("name", Config.Name, path)
-	}
+	_ = SetGitConfig("email", Config.Email, path)
+	_ = SetGitConfig("name", Config.Name, path)
 	return err
 }
 
@@ -51,7 +76,24 @@ func Fetch(path string) error {
 	return err
 }
 
+// IsDirty check if the repo has uncommitted changes
+func IsDirty(path string) (bool, error) {
+	output, err := exec.Command("git", "-C", path, "status", "--porcelain").Output()
+	return len(strings.TrimSpace(string(output))) > 0, err
+}
+
+// PullFFOnly git pull the repo, only if it can fast-forward, return the output of git
+func PullFFOnly(path string) (string, error) {
+	output, err := exec.Command("git", "-C", path, "pull", "--ff-only").CombinedOutput()
+	return string(output), err
+}
+
+// SetGitConfig set user.<key> in the repo gitconfig, skip if the value is empty,
+// so the global git config is used
 func SetGitConfig(key, value, path string) error {
+	if len(value) == 0 {
+		return nil
+	}
 	args := []string{}
 	if len(path) > 0 {
 		args = append(args, "-C", path)
@@ -61,6 +103,12 @@ func SetGitConfig(key, value, path string) error {
 	return err
 }
 
+// IsInsideWorkTree check if the current directory is in a git work tree, also from a subdirectory
+func IsInsideWorkTree() bool {
+	output, err := exec.Command("git", "rev-parse", "--is-inside-work-tree").Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
 // CurrentGitRepo return the GitRepo path
 func CurrentGitRepo() (string, error) {
 	output, err := GitCommand("rev-parse", "-q", "--show-toplevel")
@@ -76,6 +124,15 @@ func CurrentBranch() string {
 	return branch
 }
 
+// CurrentProject return the project path with namespace of the current repo remote
+func CurrentProject() string {
+	if _, err := CurrentGitRepo(); err != nil {
+		utils.Err("not a git repository, use --project to set the project")
+	}
+	remote := CurrentRemote(CurrentBranch())
+	return TransferGitURLToProject(RemoteURL(remote))
+}
+
 func RemoteURL(remote string) string {
 	gitURL, err := GitCommand("ls-remote", "--get-url", remote)
 	if err != nil {
@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCloneSkipsEmptyGitConfig(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v %s", err, out)
+	}
+
+	for _, key := range []string{"name", "email"} {
+		if err := SetGitConfig(key, "", dir); err != nil {
+			t.Fatalf("SetGitConfig(%q, \"\"): %v", key, err)
+		}
+		out, err := exec.Command("git", "-C", dir, "config", "--local", "user."+key).Output()
+		if err == nil {
+			t.Errorf("user.%s = %q, want unset", key, strings.TrimSpace(string(out)))
+		}
+	}
+
+	if err := SetGitConfig("name", "lab", dir); err != nil {
+		t.Fatalf("SetGitConfig(name, lab): %v", err)
+	}
+	out, err := exec.Command("git", "-C", dir, "config", "--local", "user.name").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "lab" {
+		t.Errorf("user.name = %q, %v, want lab", out, err)
+	}
+}
@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -17,27 +20,108 @@ import (
 var (
 	perPage    = 100
 	apiVersion = "v4"
-)
-
-const (
+	// interval between two polls of a running job or pipeline
 	interval = 3 * time.Second
-	throttle = 50 * time.Microsecond
 )
 
+const throttle = 50 * time.Microsecond
+
 func NewClient() *gitlab.Client {
 	path := gitlab.WithBaseURL(strings.Join([]string{Config.BaseURL, "api", apiVersion}, "/"))
-	client, err := gitlab.NewClient(Config.Token, path)
+	newClient := gitlab.NewClient
+	if Config.ClientID != "" {
+		newClient = gitlab.NewOAuthClient
+	}
+	client, err := newClient(Config.Token, path)
 	if err != nil {
 		utils.Err(err)
 	}
 	return client
 }
 
-// Projects will return all projects path with namespace
-func Projects(syncAll bool) []string {
+// SyncOptions the options of lab sync
+type SyncOptions struct {
+	// All sync all projects, not only the projects you are the membership
+	All bool
+	// Force ignore the projects cache
+	Force bool
+	// Topic only sync the projects with the topic
+	Topic string
+	// MinStars only sync the projects with at least MinStars stars
+	MinStars int
+	// IncludeArchived also sync the archived projects
+	IncludeArchived bool
+	// Sort the order of the projects, one of ProjectSorts, empty keeps the order of gitlab
+	Sort string
+	// DryRun doesn't write the cache
+	DryRun bool
+}
+
+// ProjectSorts the supported sorts of lab sync, the gitlab order_by and sort of each
+var ProjectSorts = map[string][2]string{
+	"name":          {"name", "asc"},
+	"last_activity": {"last_activity_at", "desc"},
+	"stars":         {"star_count", "desc"},
+	"created":       {"created_at", "desc"},
+}
+
+// cacheKey the projects synced with different filters are cached separately
+func (o SyncOptions) cacheKey() string {
+	return fmt.Sprintf("topic=%s&min_stars=%d&archived=%t&sort=%s", o.Topic, o.MinStars, o.IncludeArchived, o.Sort)
+}
+
+// Projects will return all projects with their metadata,
+// the cached projects are used if younger than cache_ttl, unless force.
+// With projects_url the projects of the url are returned instead, the filters don't apply
+func Projects(ctx context.Context, opts SyncOptions) []ProjectInfo {
+	if Config.ProjectsURL != "" {
+		projects, err := urlProjects(ctx, opts.Force)
+		utils.Check(err)
+		return projects
+	}
+	ttl := cacheTTL()
+	if !opts.Force {
+		if projects, ok := readProjectCache(ttl, opts.cacheKey()); ok {
+			return projects
+		}
+	}
 	client := NewClient()
 
-	return getAllGroupProjects(client, "linux", "kubernetes")
+	// the simple projection omits the star count, so it is only used without the stars filter
+	opt := gitlab.ListGroupProjectsOptions{Simple: gitlab.Ptr(opts.MinStars <= 0)}
+	if opts.Topic != "" {
+		opt.Topic = gitlab.Ptr(opts.Topic)
+	}
+	// the archived projects are mostly noise in the fuzzy finders
+	if !opts.IncludeArchived {
+		opt.Archived = gitlab.Ptr(false)
+	}
+	// gitlab sorts the projects of each group, the groups are still fetched in parallel
+	if sort, ok := ProjectSorts[opts.Sort]; ok {
+		opt.OrderBy = gitlab.Ptr(sort[0])
+		opt.Sort = gitlab.Ptr(sort[1])
+	}
+	projects := getAllGroupProjects(ctx, client, MainConfig.NumWorkers, opt, opts.MinStars, "linux", "kubernetes")
+	// don't cache the projects of a canceled sync
+	if ttl > 0 && ctx.Err() == nil && !opts.DryRun {
+		utils.PrintErr(writeProjectCache(projects, opts.cacheKey()))
+	}
+	return projects
+}
+
+// GroupProjects return the projects of the group and its subgroups, at most num_workers groups
+// are requested in parallel, the archived ones only with include_archived
+func GroupProjects(ctx context.Context, group string) []string {
+	opt := gitlab.ListGroupProjectsOptions{Simple: gitlab.Ptr(true)}
+	if !Config.IncludeArchived {
+		opt.Archived = gitlab.Ptr(false)
+	}
+	projects := getAllGroupProjects(ctx, NewClient(), MainConfig.NumWorkers, opt, 0, group)
+	paths := make([]string, 0, len(projects))
+	for _, p := range projects {
+		paths = append(paths, p.Path)
+	}
+	return paths
 }
 
 func projectNameSpaces(projects []*gitlab.Project) []string {
@@ -94,56 +178,98 @@ func getAllProjects(client *gitlab.Client, syncAll bool, numWorkers int) []strin
 	return allProjects
 }
 
-// getAllGroupProjects gets all projects for a specific group and all its subgroups with pagination
-func getAllGroupProjects(client *gitlab.Client, groups ...any) []string {
+// getAllGroupProjects gets all projects for a specific group and all its subgroups with pagination.
+// At most numWorkers groups are requested in parallel, opt and minStars filter the projects of each group.
+// The progress is reported after each group, it stops when ctx is canceled
+func getAllGroupProjects(ctx context.Context, client *gitlab.Client, numWorkers int, opt gitlab.ListGroupProjectsOptions, minStars int, groups ...any) []ProjectInfo {
 	allGroups := []any{}
 
 	for _, g := range groups {
 		allGroups = append(allGroups, g)
-		allGroups = append(allGroups, getAllSubgroups(client, g)...)
+		allGroups = append(allGroups, getAllSubgroups(ctx, client, g)...)
 	}
 
 	// Get projects for each group
-	var allProjects []string
+	var allProjects []ProjectInfo
 	var mu sync.Mutex
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(numWorkers, 1))
+	progress := newSyncProgress(len(allGroups))
+	defer progress.finish()
 
 	for _, gID := range allGroups {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return deduplicateProjects(allProjects)
+		}
 		wg.Add(1)
 		go func(gID any) {
 			defer wg.Done()
-			projects := getGroupProjects(client, gID)
+			defer func() { <-sem }()
+			projects := getGroupProjects(ctx, client, gID, opt, minStars)
 
 			mu.Lock()
 			allProjects = append(allProjects, projects...)
 			mu.Unlock()
+			progress.done(len(projects))
 
 			time.Sleep(throttle) // Avoid rate limiting
 		}(gID)
 	}
 
 	wg.Wait()
-	return allProjects
+	return deduplicateProjects(allProjects)
 }
 
-// getGroupProjects gets projects for a single group with pagination
-func getGroupProjects(client *gitlab.Client, groupID any) []string {
-	opt := gitlab.ListOptions{
+// deduplicateProjects drop the projects listed more than once, e.g. a subgroup listed both
+// directly and as a descendant of another group root, the first one is kept
+func deduplicateProjects(projects []ProjectInfo) []ProjectInfo {
+	seen := make(map[string]bool, len(projects))
+	unique := make([]ProjectInfo, 0, len(projects))
+	for _, p := range projects {
+		if seen[p.Path] {
+			continue
+		}
+		seen[p.Path] = true
+		unique = append(unique, p)
+	}
+	return unique
+}
+
+// getGroupProjects gets projects for a single group with pagination.
+// The api can't filter by stars, so the projects with less than minStars stars are dropped
+// from each page, opt.Simple must be false in this case, the simple projection has no star count
+func getGroupProjects(ctx context.Context, client *gitlab.Client, groupID any, opt gitlab.ListGroupProjectsOptions, minStars int) []ProjectInfo {
+	opt.ListOptions = gitlab.ListOptions{
 		PerPage: perPage,
 		Page:    1,
 	}
 
-	var projects []string
+	var projects []ProjectInfo
 	for {
-		ps, resp, err := client.Groups.ListGroupProjects(groupID, &gitlab.ListGroupProjectsOptions{ListOptions: opt})
+		var ps []*gitlab.Project
+		var resp *gitlab.Response
+		err := withRetry(ctx, func() (*gitlab.Response, error) {
+			var err error
+			ps, resp, err = client.Groups.ListGroupProjects(groupID, &opt, gitlab.WithContext(ctx))
+			return resp, err
+		})
 		if err != nil {
-			utils.PrintErr(err)
+			// canceled by ctrl-c, not an error
+			if ctx.Err() == nil {
+				utils.PrintErr(err)
+			}
 			break
 		}
 
-		// Extract path with namespace for each project
+		// Extract path with namespace and metadata for each project
 		for _, p := range ps {
-			projects = append(projects, p.PathWithNamespace)
+			if p.StarCount < minStars {
+				continue
+			}
+			projects = append(projects, ProjectInfo{Path: p.PathWithNamespace, StarCount: p.StarCount, Topics: p.Topics})
 		}
 
 		if resp.NextPage == 0 {
@@ -156,7 +282,7 @@ func getGroupProjects(client *gitlab.Client, groupID any) []string {
 }
 
 // getAllSubgroups gets all subgroups recursively for a specific group
-func getAllSubgroups(client *gitlab.Client, groupID any) []any {
+func getAllSubgroups(ctx context.Context, client *gitlab.Client, groupID any) []any {
 	opt := gitlab.ListOptions{
 		PerPage: perPage,
 		Page:    1,
@@ -165,11 +291,20 @@ func getAllSubgroups(client *gitlab.Client, groupID any) []any {
 	var allSubgroups []any
 	for {
 		// Use ListDescendantGroups to get all descendant groups (including nested subgroups)
-		subgroups, resp, err := client.Groups.ListDescendantGroups(groupID, &gitlab.ListDescendantGroupsOptions{
-			ListOptions: opt,
+		var subgroups []*gitlab.Group
+		var resp *gitlab.Response
+		err := withRetry(ctx, func() (*gitlab.Response, error) {
+			var err error
+			subgroups, resp, err = client.Groups.ListDescendantGroups(groupID, &gitlab.ListDescendantGroupsOptions{
+				ListOptions: opt,
+			}, gitlab.WithContext(ctx))
+			return resp, err
 		})
 		if err != nil {
-			utils.PrintErr(err)
+			// canceled by ctrl-c, not an error
+			if ctx.Err() == nil {
+				utils.PrintErr(err)
+			}
 			break
 		}
 
@@ -187,33 +322,154 @@ func getAllSubgroups(client *gitlab.Client, groupID any) []any {
 }
 
 // TransferGitURLToProject example:
-// git@gitlab.com/Ackerr:lab.git     -> Ackerr/lab
-// https://gitlab.com/Ackerr/lab.git -> Ackerr/lab
+// git@gitlab.com:Ackerr/lab.git            -> Ackerr/lab
+// https://gitlab.com/Ackerr/lab.git        -> Ackerr/lab
+// ssh://git@gitlab.com:2222/Ackerr/lab.git -> Ackerr/lab
 func TransferGitURLToProject(gitURL string) string {
 	var url string
-	if strings.HasPrefix(gitURL, "https://") {
-		url = gitURL[len(Config.BaseURL) : len(gitURL)-4]
-	}
-	if strings.HasPrefix(gitURL, "git@") {
-		url = gitURL[:len(gitURL)-4]
-		url = strings.Split(url, ":")[1]
+	switch {
+	case Config.BaseURL != "" && strings.HasPrefix(gitURL, Config.BaseURL+"/"):
+		// gitlab may be served under a relative path
+		url = gitURL[len(Config.BaseURL):]
+	case strings.HasPrefix(gitURL, "https://"), strings.HasPrefix(gitURL, "http://"), strings.HasPrefix(gitURL, "ssh://"):
+		// drop the scheme and the user@host:port
+		_, rest, _ := strings.Cut(gitURL, "://")
+		_, url, _ = strings.Cut(rest, "/")
+	case strings.HasPrefix(gitURL, "git@"):
+		_, url, _ = strings.Cut(gitURL, ":")
 	}
+	url = strings.TrimSuffix(strings.Trim(url, "/"), ".git")
 	return url
 }
 
-func TraceRunningJobs(client *gitlab.Client, pid any, jobs []*gitlab.Job, tailLine int64) bool {
+// PipelineList return the latest pipelines of the project, at most limit pipelines
+func PipelineList(client *gitlab.Client, pid any, opt *gitlab.ListProjectPipelinesOptions, limit int) []*gitlab.PipelineInfo {
+	opt.PerPage = min(limit, perPage)
+	opt.Page = 1
+
+	var pipelines []*gitlab.PipelineInfo
+	for {
+		ps, resp, err := client.Pipelines.ListProjectPipelines(pid, opt)
+		utils.Check(err)
+		pipelines = append(pipelines, ps...)
+		if len(pipelines) >= limit {
+			return pipelines[:limit]
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return pipelines
+}
+
+// TriggerPipeline create a new pipeline for the ref with the variables
+func TriggerPipeline(client *gitlab.Client, pid any, ref string, variables map[string]string) (*gitlab.Pipeline, error) {
+	vars := make([]*gitlab.PipelineVariableOptions, 0, len(variables))
+	for key, value := range variables {
+		vars = append(vars, &gitlab.PipelineVariableOptions{
+			Key:   gitlab.Ptr(key),
+			Value: gitlab.Ptr(value),
+		})
+	}
+	pipeline, _, err := client.Pipelines.CreatePipeline(pid, &gitlab.CreatePipelineOptions{
+		Ref:       gitlab.Ptr(ref),
+		Variables: &vars,
+	})
+	return pipeline, err
+}
+
+// LatestPipeline return the latest pipeline of the ref
+func LatestPipeline(client *gitlab.Client, pid any, ref string) (*gitlab.Pipeline, error) {
+	pipeline, _, err := client.Pipelines.GetLatestPipeline(pid, &gitlab.GetLatestPipelineOptions{Ref: gitlab.Ptr(ref)})
+	return pipeline, err
+}
+
+// GetPipeline return the pipeline of the project
+func GetPipeline(client *gitlab.Client, pid any, pipelineID int) (*gitlab.Pipeline, error) {
+	pipeline, _, err := client.Pipelines.GetPipeline(pid, pipelineID)
+	return pipeline, err
+}
+
+// WatchPipeline call fn with the pipeline and its jobs every interval, until the pipeline
+// is no longer running. It returns ctx.Err() when ctx is canceled
+func WatchPipeline(ctx context.Context, client *gitlab.Client, pid any, pipelineID int, fn func(*gitlab.Pipeline, []*gitlab.Job)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pipeline, _, err := client.Pipelines.GetPipeline(pid, pipelineID, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		fn(pipeline, ListPipelineJobs(client, pid, pipelineID))
+		if !IsRunning(pipeline.Status) {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// CancelPipeline cancel the running jobs of the pipeline
+func CancelPipeline(client *gitlab.Client, pid any, pipelineID int) (*gitlab.Pipeline, error) {
+	pipeline, _, err := client.Pipelines.CancelPipelineBuild(pid, pipelineID)
+	return pipeline, err
+}
+
+// RetryPipeline retry the failed or canceled jobs of the pipeline
+func RetryPipeline(client *gitlab.Client, pid any, pipelineID int) (*gitlab.Pipeline, error) {
+	pipeline, _, err := client.Pipelines.RetryPipelineBuild(pid, pipelineID)
+	return pipeline, err
+}
+
+// ListPipelineJobs return all jobs of the pipeline
+func ListPipelineJobs(client *gitlab.Client, pid any, pipelineID int) []*gitlab.Job {
+	opt := &gitlab.ListJobsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var jobs []*gitlab.Job
+	for {
+		js, resp, err := client.Jobs.ListPipelineJobs(pid, pipelineID, opt)
+		utils.Check(err)
+		jobs = append(jobs, js...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return jobs
+}
+
+// ResolveJobByName return the first job of the pipeline whose name contains name, case-insensitive
+func ResolveJobByName(client *gitlab.Client, pid any, pipelineID int, name string) (*gitlab.Job, error) {
+	for _, job := range ListPipelineJobs(client, pid, pipelineID) {
+		if strings.Contains(strings.ToLower(job.Name), strings.ToLower(name)) {
+			return job, nil
+		}
+	}
+	return nil, fmt.Errorf("no job matches %q in pipeline %d", name, pipelineID)
+}
+
+// TraceRunningJobs trace the running jobs in parallel, the prefix color of a job depends on
+// its index in jobs, so the same jobs always get the same colors
+func TraceRunningJobs(ctx context.Context, client *gitlab.Client, pid any, jobs []*gitlab.Job, tailLine int64) bool {
 	wg := sync.WaitGroup{}
 	allDone := true
-	for _, job := range jobs {
+	for i, job := range jobs {
 		if !IsRunning(job.Status) {
 			continue
 		}
 		allDone = false
 		wg.Add(1)
-		go func(j *gitlab.Job) {
-			_ = DoTrace(client, pid, j, tailLine)
+		go func(i int, j *gitlab.Job) {
+			prefix := utils.IndexColor(fmt.Sprintf("[%s] ", j.Name), i)
+			if err := doTrace(ctx, client, os.Stdout, pid, j, tailLine, prefix); !errors.Is(err, context.Canceled) {
+				utils.PrintErr(err)
+			}
 			wg.Done()
-		}(job)
+		}(i, job)
 	}
 	wg.Wait()
 	return allDone
@@ -231,31 +487,83 @@ func IsRunning(status string) bool {
 // It will make prefix failure, so replace it. PS: \x1b == ^[
 var re = regexp.MustCompile(`\x1b\[0m.*\[0K`)
 
-func DoTrace(client *gitlab.Client, pid any, job *gitlab.Job, tailLine int64) error {
-	var offset int64
-	firstTail := true
-	prefix := utils.RandomColor(fmt.Sprintf("[%s] ", job.Name))
-	for range time.NewTicker(interval).C {
-		trace, _, err := client.Jobs.GetTraceFile(pid, job.ID)
-		utils.Check(err)
-		buffer, err := io.ReadAll(trace)
-		utils.Check(err)
-		lines := strings.Split(string(buffer), "\n")
-		length := len(lines)
-		if firstTail {
-			begin := max(int64(length)-tailLine, 0)
-			lines = lines[begin : len(lines)-1]
-			firstTail = false
-		}
-		for _, line := range lines[offset:] {
-			println(re.ReplaceAllString(prefix+line, ``))
+// DoTrace write the job log to w with the job name as prefix, it keeps polling until the job finished.
+// Only the last tailLine lines of the existing log are written, 0 writes the whole log and
+// a negative tailLine only the lines written from now on. It returns ctx.Err() when ctx is canceled
+func DoTrace(ctx context.Context, client *gitlab.Client, w io.Writer, pid any, job *gitlab.Job, tailLine int64) error {
+	return doTrace(ctx, client, w, pid, job, tailLine, utils.RandomColor(fmt.Sprintf("[%s] ", job.Name)))
+}
+
+func doTrace(ctx context.Context, client *gitlab.Client, w io.Writer, pid any, job *gitlab.Job, tailLine int64, prefix string) error {
+	t := &tracer{ctx: ctx, client: client, w: w, pid: pid, jobID: job.ID, prefix: prefix, offset: -1, tailLine: tailLine}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.fetch(!IsRunning(job.Status)); err != nil {
+			return err
 		}
-		offset = int64(length)
 		if !IsRunning(job.Status) {
 			return nil
 		}
-		job, _, err = client.Jobs.GetJob(pid, job.ID)
-		utils.Check(err)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		var err error
+		job, _, err = client.Jobs.GetJob(pid, job.ID, gitlab.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		// the job may finish between two ticks, fetch the rest of the log once more
+		if !IsRunning(job.Status) {
+			return t.fetch(true)
+		}
+	}
+}
+
+// tracer keep the offset of the job log already written
+type tracer struct {
+	ctx      context.Context
+	client   *gitlab.Client
+	w        io.Writer
+	pid      any
+	jobID    int
+	prefix   string
+	offset   int
+	tailLine int64
+}
+
+// fetch write the new lines of the job log, the last line is written only when the job
+// finished, the line may be incomplete while running
+func (t *tracer) fetch(finished bool) error {
+	trace, _, err := t.client.Jobs.GetTraceFile(t.pid, t.jobID, gitlab.WithContext(t.ctx))
+	if err != nil {
+		return err
+	}
+	buffer, err := io.ReadAll(trace)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(buffer), "\n")
+	if !finished || lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	// on the first fetch, tailLine 0 means from the beginning, negative means only the new lines
+	if t.offset < 0 {
+		switch {
+		case t.tailLine == 0:
+			t.offset = 0
+		case t.tailLine < 0:
+			t.offset = len(lines)
+		default:
+			t.offset = max(len(lines)-int(t.tailLine), 0)
+		}
+	}
+	for _, line := range lines[min(t.offset, len(lines)):] {
+		fmt.Fprintln(t.w, re.ReplaceAllString(t.prefix+line, ``))
 	}
+	t.offset = max(t.offset, len(lines))
 	return nil
 }
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestTransferGitURLToProject(t *testing.T) {
+	defer func(c *gitlabConfig) { Config = c }(Config)
+
+	tests := []struct {
+		name    string
+		baseURL string
+		gitURL  string
+		want    string
+	}{
+		{"git@", "https://gitlab.com", "git@gitlab.com:Ackerr/lab.git", "Ackerr/lab"},
+		{"https", "https://gitlab.com", "https://gitlab.com/Ackerr/lab.git", "Ackerr/lab"},
+		{"ssh with port", "https://gitlab.com", "ssh://git@gitlab.com:2222/Ackerr/lab.git", "Ackerr/lab"},
+		{"trailing slash", "https://gitlab.com", "https://gitlab.com/Ackerr/lab/", "Ackerr/lab"},
+		{"no .git suffix", "https://gitlab.com", "git@gitlab.com:group/sub/lab", "group/sub/lab"},
+		{"relative path", "https://example.com/gitlab", "https://example.com/gitlab/Ackerr/lab.git", "Ackerr/lab"},
+		{"relative path ssh", "https://example.com/gitlab", "git@example.com:Ackerr/lab.git", "Ackerr/lab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Config = &gitlabConfig{BaseURL: tt.baseURL}
+			if got := TransferGitURLToProject(tt.gitURL); got != tt.want {
+				t.Errorf("TransferGitURLToProject(%q) = %q, want %q", tt.gitURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoTraceJobFinishedMidPoll(t *testing.T) {
+	defer func(d time.Duration) { interval = d }(interval)
+	interval = time.Millisecond
+
+	// each poll of the job grows the log, the second poll finds the job finished
+	traces := []string{"l1\nl2\nl", "l1\nl2\nl3\nl4\nl", "l1\nl2\nl3\nl4\nl5\nl6"}
+	var mu sync.Mutex
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v4/projects/1/jobs/5/trace":
+			fmt.Fprint(w, traces[polls])
+		case "/api/v4/projects/1/jobs/5":
+			polls++
+			status := "running"
+			if polls == len(traces)-1 {
+				status = "success"
+			}
+			fmt.Fprintf(w, `{"id": 5, "name": "test", "status": %q}`, status)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL+"/api/v4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	job := &gitlab.Job{ID: 5, Name: "test", Status: "running"}
+	if err = doTrace(context.Background(), client, &buf, 1, job, 0, ""); err != nil {
+		t.Fatalf("doTrace: %v", err)
+	}
+	if want := "l1\nl2\nl3\nl4\nl5\nl6\n"; buf.String() != want {
+		t.Errorf("doTrace wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDeduplicateProjects(t *testing.T) {
+	// linux/sub is synced directly and as a subgroup of linux, kubernetes shares linux/kernel
+	projects := []ProjectInfo{
+		{Path: "linux/kernel", StarCount: 10},
+		{Path: "linux/sub/tool", StarCount: 1},
+		{Path: "linux/sub/tool", StarCount: 2},
+		{Path: "kubernetes/k8s", StarCount: 3, Topics: []string{"go"}},
+		{Path: "linux/kernel", StarCount: 11},
+	}
+	want := []ProjectInfo{
+		{Path: "linux/kernel", StarCount: 10},
+		{Path: "linux/sub/tool", StarCount: 1},
+		{Path: "kubernetes/k8s", StarCount: 3, Topics: []string{"go"}},
+	}
+	if got := deduplicateProjects(projects); !reflect.DeepEqual(got, want) {
+		t.Errorf("deduplicateProjects() = %+v, want %+v", got, want)
+	}
+	if got := deduplicateProjects(nil); len(got) != 0 {
+		t.Errorf("deduplicateProjects(nil) = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListGroups return the groups of the user matching the search, empty search means all.
+// allAvailable include the groups the user is not a member of, it requires an admin token
+func ListGroups(client *gitlab.Client, search string, allAvailable bool) []*gitlab.Group {
+	opt := &gitlab.ListGroupsOptions{
+		AllAvailable: gitlab.Ptr(allAvailable),
+		ListOptions:  gitlab.ListOptions{PerPage: perPage, Page: 1},
+	}
+	if search != "" {
+		opt.Search = gitlab.Ptr(search)
+	}
+
+	var groups []*gitlab.Group
+	for {
+		gs, resp, err := client.Groups.ListGroups(opt)
+		utils.Check(err)
+		groups = append(groups, gs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return groups
+}
+
+// AccessLevels the human readable names of the gitlab access levels
+var AccessLevels = map[gitlab.AccessLevelValue]string{
+	gitlab.MinimalAccessPermissions: "Minimal",
+	gitlab.GuestPermissions:         "Guest",
+	gitlab.ReporterPermissions:      "Reporter",
+	gitlab.DeveloperPermissions:     "Developer",
+	gitlab.MaintainerPermissions:    "Maintainer",
+	gitlab.OwnerPermissions:         "Owner",
+}
+
+// ParseAccessLevel return the access level of the name, case-insensitive
+func ParseAccessLevel(name string) (gitlab.AccessLevelValue, error) {
+	for level, n := range AccessLevels {
+		if strings.EqualFold(n, name) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown access level %s, use guest, reporter, developer, maintainer or owner", name)
+}
+
+// ListGroupMembers return the members of the group, the inherited members are not included
+func ListGroupMembers(client *gitlab.Client, groupID any) []*gitlab.GroupMember {
+	opt := &gitlab.ListGroupMembersOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var members []*gitlab.GroupMember
+	for {
+		ms, resp, err := client.Groups.ListGroupMembers(groupID, opt)
+		utils.Check(err)
+		members = append(members, ms...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return members
+}
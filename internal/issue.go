@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListIssues return the project issues, empty filters are ignored,
+// state is one of open, closed or all, label can be comma separated
+func ListIssues(client *gitlab.Client, pid any, state, assignee, label, milestone string) []*gitlab.Issue {
+	opt := &gitlab.ListProjectIssuesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	opt.State, opt.AssigneeUsername, opt.Labels, opt.Milestone = issueFilters(state, assignee, label, milestone)
+
+	var issues []*gitlab.Issue
+	for {
+		is, resp, err := client.Issues.ListProjectIssues(pid, opt)
+		utils.Check(err)
+		issues = append(issues, is...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return issues
+}
+
+// ListGroupIssues return the issues of all projects in the group, the filters are the same as ListIssues
+func ListGroupIssues(client *gitlab.Client, gid any, state, assignee, label, milestone string) []*gitlab.Issue {
+	opt := &gitlab.ListGroupIssuesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	opt.State, opt.AssigneeUsername, opt.Labels, opt.Milestone = issueFilters(state, assignee, label, milestone)
+
+	var issues []*gitlab.Issue
+	for {
+		is, resp, err := client.Issues.ListGroupIssues(gid, opt)
+		utils.Check(err)
+		issues = append(issues, is...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return issues
+}
+
+func issueFilters(state, assignee, label, milestone string) (*string, *string, *gitlab.LabelOptions, *string) {
+	var stateOpt, assigneeOpt, milestoneOpt *string
+	var labelOpt *gitlab.LabelOptions
+	if state == "open" {
+		state = "opened"
+	}
+	if state != "" && state != "all" {
+		stateOpt = gitlab.Ptr(state)
+	}
+	if assignee != "" {
+		assigneeOpt = gitlab.Ptr(assignee)
+	}
+	if label != "" {
+		labels := gitlab.LabelOptions(strings.Split(label, ","))
+		labelOpt = &labels
+	}
+	if milestone != "" {
+		milestoneOpt = gitlab.Ptr(milestone)
+	}
+	return stateOpt, assigneeOpt, labelOpt, milestoneOpt
+}
+
+// CreateIssueOptions the optional fields of a new issue, Milestone is the milestone title,
+// DueDate the date like 2006-01-02
+type CreateIssueOptions struct {
+	Labels    []string
+	Assignee  string
+	Milestone string
+	DueDate   string
+}
+
+// CreateIssue create a project issue
+func CreateIssue(client *gitlab.Client, pid any, title, description string, opts CreateIssueOptions) (*gitlab.Issue, error) {
+	opt := &gitlab.CreateIssueOptions{
+		Title:       gitlab.Ptr(title),
+		Description: gitlab.Ptr(description),
+	}
+	if len(opts.Labels) > 0 {
+		opt.Labels = (*gitlab.LabelOptions)(&opts.Labels)
+	}
+	if opts.Assignee != "" {
+		id, err := userID(client, opts.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		opt.AssigneeIDs = &[]int{id}
+	}
+	if opts.Milestone != "" {
+		id, err := milestoneID(client, pid, opts.Milestone)
+		if err != nil {
+			return nil, err
+		}
+		opt.MilestoneID = gitlab.Ptr(id)
+	}
+	if opts.DueDate != "" {
+		due, err := gitlab.ParseISOTime(opts.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date %s, use YYYY-MM-DD", opts.DueDate)
+		}
+		opt.DueDate = &due
+	}
+	issue, _, err := client.Issues.CreateIssue(pid, opt)
+	return issue, err
+}
+
+// GetIssue return the project issue
+func GetIssue(client *gitlab.Client, pid any, issueID int) (*gitlab.Issue, error) {
+	issue, _, err := client.Issues.GetIssue(pid, issueID)
+	return issue, err
+}
+
+// AssignIssue replace the assignees of the issue with the users, empty userIDs unassign all
+func AssignIssue(client *gitlab.Client, pid any, issueID int, userIDs []int) error {
+	_, _, err := client.Issues.UpdateIssue(pid, issueID, &gitlab.UpdateIssueOptions{AssigneeIDs: &userIDs})
+	return err
+}
+
+// CloseIssue close the project issue
+func CloseIssue(client *gitlab.Client, pid any, issueID int) error {
+	_, _, err := client.Issues.UpdateIssue(pid, issueID, &gitlab.UpdateIssueOptions{StateEvent: gitlab.Ptr("close")})
+	return err
+}
+
+// ReopenIssue reopen the closed project issue
+func ReopenIssue(client *gitlab.Client, pid any, issueID int) error {
+	_, _, err := client.Issues.UpdateIssue(pid, issueID, &gitlab.UpdateIssueOptions{StateEvent: gitlab.Ptr("reopen")})
+	return err
+}
+
+// AddIssueNote add a comment to the issue
+func AddIssueNote(client *gitlab.Client, pid any, issueID int, body string) (*gitlab.Note, error) {
+	note, _, err := client.Notes.CreateIssueNote(pid, issueID, &gitlab.CreateIssueNoteOptions{Body: gitlab.Ptr(body)})
+	return note, err
+}
+
+// milestoneID return the id of the project milestone with the title
+func milestoneID(client *gitlab.Client, pid any, title string) (int, error) {
+	milestones, _, err := client.Milestones.ListMilestones(pid, &gitlab.ListMilestonesOptions{Title: gitlab.Ptr(title)})
+	if err != nil {
+		return 0, err
+	}
+	if len(milestones) == 0 {
+		return 0, fmt.Errorf("milestone %s not found", title)
+	}
+	return milestones[0].ID, nil
+}
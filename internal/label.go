@@ -0,0 +1,63 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListLabels return the labels of the project, including the ones of its groups
+func ListLabels(client *gitlab.Client, pid any) []*gitlab.Label {
+	opt := &gitlab.ListLabelsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var labels []*gitlab.Label
+	for {
+		ls, resp, err := client.Labels.ListLabels(pid, opt)
+		utils.Check(err)
+		labels = append(labels, ls...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return labels
+}
+
+// ListGroupLabels return the labels of the group, the group and project labels have the same fields
+func ListGroupLabels(client *gitlab.Client, gid any) []*gitlab.Label {
+	opt := &gitlab.ListGroupLabelsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var labels []*gitlab.Label
+	for {
+		ls, resp, err := client.GroupLabels.ListGroupLabels(gid, opt)
+		utils.Check(err)
+		for _, l := range ls {
+			labels = append(labels, (*gitlab.Label)(l))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return labels
+}
+
+// CreateLabel create a label of the project, color is like #d9534f or a css color name
+func CreateLabel(client *gitlab.Client, pid any, name, color, description string) (*gitlab.Label, error) {
+	opt := &gitlab.CreateLabelOptions{Name: gitlab.Ptr(name), Color: gitlab.Ptr(color)}
+	if description != "" {
+		opt.Description = gitlab.Ptr(description)
+	}
+	label, _, err := client.Labels.CreateLabel(pid, opt)
+	return label, err
+}
+
+// CreateGroupLabel create a label of the group, available in all its projects
+func CreateGroupLabel(client *gitlab.Client, gid any, name, color, description string) (*gitlab.Label, error) {
+	opt := &gitlab.CreateGroupLabelOptions{Name: gitlab.Ptr(name), Color: gitlab.Ptr(color)}
+	if description != "" {
+		opt.Description = gitlab.Ptr(description)
+	}
+	label, _, err := client.GroupLabels.CreateGroupLabel(gid, opt)
+	return (*gitlab.Label)(label), err
+}
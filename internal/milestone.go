@@ -0,0 +1,75 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListMilestones return the milestones of the project, state is active, closed or empty for all
+func ListMilestones(client *gitlab.Client, pid any, state string) []*gitlab.Milestone {
+	opt := &gitlab.ListMilestonesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	if state != "" {
+		opt.State = gitlab.Ptr(state)
+	}
+
+	var milestones []*gitlab.Milestone
+	for {
+		ms, resp, err := client.Milestones.ListMilestones(pid, opt)
+		utils.Check(err)
+		milestones = append(milestones, ms...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return milestones
+}
+
+// ListGroupMilestones return the milestones of the group as project milestones without project id
+func ListGroupMilestones(client *gitlab.Client, gid any, state string) []*gitlab.Milestone {
+	opt := &gitlab.ListGroupMilestonesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	if state != "" {
+		opt.State = gitlab.Ptr(state)
+	}
+
+	var milestones []*gitlab.Milestone
+	for {
+		ms, resp, err := client.GroupMilestones.ListGroupMilestones(gid, opt)
+		utils.Check(err)
+		for _, m := range ms {
+			milestones = append(milestones, &gitlab.Milestone{
+				ID:          m.ID,
+				IID:         m.IID,
+				GroupID:     m.GroupID,
+				Title:       m.Title,
+				Description: m.Description,
+				StartDate:   m.StartDate,
+				DueDate:     m.DueDate,
+				State:       m.State,
+				UpdatedAt:   m.UpdatedAt,
+				CreatedAt:   m.CreatedAt,
+				Expired:     m.Expired,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return milestones
+}
+
+// CreateMilestone create a milestone of the project, dueDate is like 2024-12-31 or empty
+func CreateMilestone(client *gitlab.Client, pid any, title, dueDate string) (*gitlab.Milestone, error) {
+	opt := &gitlab.CreateMilestoneOptions{Title: gitlab.Ptr(title)}
+	if dueDate != "" {
+		due, err := gitlab.ParseISOTime(dueDate)
+		if err != nil {
+			return nil, err
+		}
+		opt.DueDate = gitlab.Ptr(due)
+	}
+	milestone, _, err := client.Milestones.CreateMilestone(pid, opt)
+	return milestone, err
+}
@@ -0,0 +1,327 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+const draftPrefix = "Draft: "
+
+// draftRe match the draft markers gitlab recognizes at the start of the title, a title
+// like "Draft release notes" is not a draft
+var draftRe = regexp.MustCompile(`(?i)^\s*(\[draft\]|\(draft\)|draft:|wip:)\s*`)
+
+// ListMergeRequests return the project merge requests in the state,
+// state is one of open, merged, closed or all
+func ListMergeRequests(client *gitlab.Client, pid any, state string, opt *gitlab.ListProjectMergeRequestsOptions) []*gitlab.BasicMergeRequest {
+	if state == "open" {
+		state = "opened"
+	}
+	if state != "" && state != "all" {
+		opt.State = gitlab.Ptr(state)
+	}
+	opt.PerPage = perPage
+	opt.Page = 1
+
+	var mrs []*gitlab.BasicMergeRequest
+	for {
+		ms, resp, err := client.MergeRequests.ListProjectMergeRequests(pid, opt)
+		utils.Check(err)
+		mrs = append(mrs, ms...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return mrs
+}
+
+// ListWIPMRs return the open draft merge requests of the project
+func ListWIPMRs(client *gitlab.Client, pid any) []*gitlab.BasicMergeRequest {
+	return ListMergeRequests(client, pid, "open", &gitlab.ListProjectMergeRequestsOptions{WIP: gitlab.Ptr("yes")})
+}
+
+// CreateMROptions the optional fields of a new merge request
+type CreateMROptions struct {
+	Assignee           string
+	Labels             []string
+	Draft              bool
+	RemoveSourceBranch bool
+}
+
+// CreateMergeRequest create a merge request from the source branch to the target branch
+func CreateMergeRequest(client *gitlab.Client, pid any, sourceBranch, targetBranch, title, description string, opts CreateMROptions) (*gitlab.MergeRequest, error) {
+	if opts.Draft && !strings.HasPrefix(title, draftPrefix) {
+		title = draftPrefix + title
+	}
+	opt := &gitlab.CreateMergeRequestOptions{
+		Title:              gitlab.Ptr(title),
+		Description:        gitlab.Ptr(description),
+		SourceBranch:       gitlab.Ptr(sourceBranch),
+		TargetBranch:       gitlab.Ptr(targetBranch),
+		RemoveSourceBranch: gitlab.Ptr(opts.RemoveSourceBranch),
+	}
+	if len(opts.Labels) > 0 {
+		opt.Labels = (*gitlab.LabelOptions)(&opts.Labels)
+	}
+	if opts.Assignee != "" {
+		id, err := userID(client, opts.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		opt.AssigneeID = gitlab.Ptr(id)
+	}
+	mr, _, err := client.MergeRequests.CreateMergeRequest(pid, opt)
+	return mr, err
+}
+
+// FindMergeRequest return the open merge request of the source branch, nil if not exist
+func FindMergeRequest(client *gitlab.Client, pid any, sourceBranch string) *gitlab.BasicMergeRequest {
+	mrs := ListMergeRequests(client, pid, "open", &gitlab.ListProjectMergeRequestsOptions{
+		SourceBranch: gitlab.Ptr(sourceBranch),
+	})
+	if len(mrs) == 0 {
+		return nil
+	}
+	return mrs[0]
+}
+
+// DefaultBranch return the default branch of the project
+func DefaultBranch(client *gitlab.Client, pid any) (string, error) {
+	project, _, err := client.Projects.GetProject(pid, nil)
+	if err != nil {
+		return "", err
+	}
+	return project.DefaultBranch, nil
+}
+
+// GetMergeRequest return the merge request of the project
+func GetMergeRequest(client *gitlab.Client, pid any, mrID int) (*gitlab.MergeRequest, error) {
+	mr, _, err := client.MergeRequests.GetMergeRequest(pid, mrID, nil)
+	return mr, err
+}
+
+// ApproveMR approve the merge request as the current user
+func ApproveMR(client *gitlab.Client, pid any, mrID int) error {
+	_, _, err := client.MergeRequestApprovals.ApproveMergeRequest(pid, mrID, nil)
+	return err
+}
+
+// ApprovalsLeft return the number of approvals still required by the merge request
+func ApprovalsLeft(client *gitlab.Client, pid any, mrID int) (int, error) {
+	approvals, _, err := client.MergeRequestApprovals.GetConfiguration(pid, mrID)
+	if err != nil {
+		return 0, err
+	}
+	return approvals.ApprovalsLeft, nil
+}
+
+// MergeMR merge the merge request, msg is the merge or squash commit message if not empty
+func MergeMR(client *gitlab.Client, pid any, mrID int, squash bool, msg string) error {
+	opt := &gitlab.AcceptMergeRequestOptions{Squash: gitlab.Ptr(squash)}
+	if msg != "" {
+		if squash {
+			opt.SquashCommitMessage = gitlab.Ptr(msg)
+		} else {
+			opt.MergeCommitMessage = gitlab.Ptr(msg)
+		}
+	}
+	_, _, err := client.MergeRequests.AcceptMergeRequest(pid, mrID, opt)
+	return err
+}
+
+// AssignMR replace the assignees of the merge request, no user ids unassign everyone
+func AssignMR(client *gitlab.Client, pid any, mrID int, userIDs []int) error {
+	_, _, err := client.MergeRequests.UpdateMergeRequest(pid, mrID, &gitlab.UpdateMergeRequestOptions{AssigneeIDs: &userIDs})
+	return err
+}
+
+// UpdateMR update the fields of the merge request set in opt
+func UpdateMR(client *gitlab.Client, pid any, mrID int, opt *gitlab.UpdateMergeRequestOptions) (*gitlab.MergeRequest, error) {
+	mr, _, err := client.MergeRequests.UpdateMergeRequest(pid, mrID, opt)
+	return mr, err
+}
+
+// UpdateMRLabels add and remove labels of the merge request, the other labels are kept
+func UpdateMRLabels(client *gitlab.Client, pid any, mrID int, add, remove []string) error {
+	opt := &gitlab.UpdateMergeRequestOptions{}
+	if len(add) > 0 {
+		opt.AddLabels = (*gitlab.LabelOptions)(&add)
+	}
+	if len(remove) > 0 {
+		opt.RemoveLabels = (*gitlab.LabelOptions)(&remove)
+	}
+	_, _, err := client.MergeRequests.UpdateMergeRequest(pid, mrID, opt)
+	return err
+}
+
+// SetMRDraft mark the merge request as draft with the Draft: title prefix,
+// or as ready by removing the draft prefix of the title
+func SetMRDraft(client *gitlab.Client, pid any, mrID int, draft bool) error {
+	mr, err := GetMergeRequest(client, pid, mrID)
+	if err != nil {
+		return err
+	}
+	title := draftRe.ReplaceAllString(mr.Title, "")
+	if draft {
+		title = draftPrefix + title
+	}
+	_, _, err = client.MergeRequests.UpdateMergeRequest(pid, mrID, &gitlab.UpdateMergeRequestOptions{Title: gitlab.Ptr(title)})
+	return err
+}
+
+// CheckMRConflicts return true if the source branch of the merge request conflicts with the target branch
+func CheckMRConflicts(client *gitlab.Client, pid any, mrID int) (bool, error) {
+	mr, err := GetMergeRequest(client, pid, mrID)
+	if err != nil {
+		return false, err
+	}
+	return mr.HasConflicts, nil
+}
+
+// RebaseMR start the rebase of the merge request source branch onto the target branch,
+// the rebase runs in the background
+func RebaseMR(client *gitlab.Client, pid any, mrID int) error {
+	_, err := client.MergeRequests.RebaseMergeRequest(pid, mrID, nil)
+	return err
+}
+
+// WaitRebase poll the merge request until the rebase finished, the merge error of the
+// returned merge request is set if the rebase failed
+func WaitRebase(ctx context.Context, client *gitlab.Client, pid any, mrID int) (*gitlab.MergeRequest, error) {
+	opt := &gitlab.GetMergeRequestsOptions{IncludeRebaseInProgress: gitlab.Ptr(true)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		mr, _, err := client.MergeRequests.GetMergeRequest(pid, mrID, opt, gitlab.WithContext(ctx))
+		if err != nil || !mr.RebaseInProgress {
+			return mr, err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// GetMRDiff return the diffs of the files changed in the merge request
+func GetMRDiff(client *gitlab.Client, pid any, mrID int) ([]*gitlab.MergeRequestDiff, error) {
+	opt := &gitlab.ListMergeRequestDiffsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var diffs []*gitlab.MergeRequestDiff
+	for {
+		ds, resp, err := client.MergeRequests.ListMergeRequestDiffs(pid, mrID, opt)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, ds...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return diffs, nil
+}
+
+// ListMRDiscussions return the comment threads of the merge request, a top-level comment
+// without replies is a discussion with a single note
+func ListMRDiscussions(client *gitlab.Client, pid any, mrID int) []*gitlab.Discussion {
+	opt := &gitlab.ListMergeRequestDiscussionsOptions{PerPage: perPage, Page: 1}
+
+	var discussions []*gitlab.Discussion
+	for {
+		ds, resp, err := client.Discussions.ListMergeRequestDiscussions(pid, mrID, opt)
+		utils.Check(err)
+		discussions = append(discussions, ds...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return discussions
+}
+
+// AddMRNote add a top-level comment to the merge request
+func AddMRNote(client *gitlab.Client, pid any, mrID int, body string) (*gitlab.Note, error) {
+	note, _, err := client.Notes.CreateMergeRequestNote(pid, mrID, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+	return note, err
+}
+
+// ReplyMRNote reply to the thread of the note
+func ReplyMRNote(client *gitlab.Client, pid any, mrID, noteID int, body string) (*gitlab.Note, error) {
+	for _, d := range ListMRDiscussions(client, pid, mrID) {
+		for _, n := range d.Notes {
+			if n.ID != noteID {
+				continue
+			}
+			note, _, err := client.Discussions.AddMergeRequestDiscussionNote(pid, mrID, d.ID, &gitlab.AddMergeRequestDiscussionNoteOptions{Body: gitlab.Ptr(body)})
+			return note, err
+		}
+	}
+	return nil, fmt.Errorf("note %d not found in merge request !%d", noteID, mrID)
+}
+
+// MRStatusReport is the merge readiness of a merge request
+type MRStatusReport struct {
+	IID               int    `json:"iid"`
+	Title             string `json:"title"`
+	State             string `json:"state"`
+	Draft             bool   `json:"draft"`
+	WebURL            string `json:"web_url"`
+	PipelineStatus    string `json:"pipeline_status"`
+	ApprovalsRequired int    `json:"approvals_required"`
+	ApprovalsGiven    int    `json:"approvals_given"`
+	ApprovalsLeft     int    `json:"approvals_left"`
+	UnresolvedThreads int    `json:"unresolved_threads"`
+	HasConflicts      bool   `json:"has_conflicts"`
+}
+
+// Ready report if the merge request can be merged, a merge request without pipeline is
+// not blocked by the pipeline
+func (r *MRStatusReport) Ready() bool {
+	pipelineOK := r.PipelineStatus == "" || r.PipelineStatus == "success"
+	return r.State == "opened" && !r.Draft && pipelineOK && r.ApprovalsLeft == 0 && r.UnresolvedThreads == 0 && !r.HasConflicts
+}
+
+// MRStatus aggregate the state, head pipeline, approvals, unresolved threads and
+// conflicts of the merge request
+func MRStatus(client *gitlab.Client, pid any, mrID int) (*MRStatusReport, error) {
+	mr, err := GetMergeRequest(client, pid, mrID)
+	if err != nil {
+		return nil, err
+	}
+	report := &MRStatusReport{
+		IID:          mr.IID,
+		Title:        mr.Title,
+		State:        mr.State,
+		Draft:        mr.Draft,
+		WebURL:       mr.WebURL,
+		HasConflicts: mr.HasConflicts,
+	}
+	if mr.HeadPipeline != nil {
+		report.PipelineStatus = mr.HeadPipeline.Status
+	}
+
+	approvals, _, err := client.MergeRequestApprovals.GetConfiguration(pid, mrID)
+	if err != nil {
+		return nil, err
+	}
+	report.ApprovalsRequired = approvals.ApprovalsRequired
+	report.ApprovalsGiven = len(approvals.ApprovedBy)
+	report.ApprovalsLeft = approvals.ApprovalsLeft
+
+	for _, d := range ListMRDiscussions(client, pid, mrID) {
+		if len(d.Notes) > 0 && d.Notes[0].Resolvable && !d.Notes[0].Resolved {
+			report.UnresolvedThreads++
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ackerr/lab/utils"
+)
+
+const (
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	oauthScope      = "api"
+	// refresh a little earlier, the token may expire during the command
+	expiryDelta = time.Minute
+)
+
+type oauthToken struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type deviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+	Error                   string `json:"error"`
+	ErrorDescription        string `json:"error_description"`
+}
+
+// tokenExpired check if the oauth token need to refresh,
+// personal access token (no client_id) never refresh
+func tokenExpired() bool {
+	if Config.ClientID == "" {
+		return false
+	}
+	if Config.Token == "" {
+		return true
+	}
+	if Config.TokenExpiry == "" {
+		return false
+	}
+	expiry, err := time.Parse(time.RFC3339, Config.TokenExpiry)
+	if err != nil {
+		return true
+	}
+	return time.Now().Add(expiryDelta).After(expiry)
+}
+
+// RefreshToken renew the oauth access token, use the refresh token if exist,
+// otherwise run the device authorization grant. The new token is written back
+// to the config file
+func RefreshToken() {
+	var token *oauthToken
+	var err error
+	if Config.RefreshToken != "" {
+		token, err = refreshGrant()
+		utils.PrintErr(err)
+	}
+	if token == nil {
+		token, err = deviceGrant()
+		utils.Check(err)
+	}
+	Config.Token = token.AccessToken
+	Config.RefreshToken = token.RefreshToken
+	Config.TokenExpiry = ""
+	if token.ExpiresIn > 0 {
+		expiry := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		Config.TokenExpiry = expiry.Format(time.RFC3339)
+	}
+	err = SaveConfig(configSection, map[string]any{
+		"token":         Config.Token,
+		"refresh_token": Config.RefreshToken,
+		"token_expiry":  Config.TokenExpiry,
+	})
+	utils.Check(err)
+}
+
+func refreshGrant() (*oauthToken, error) {
+	token := &oauthToken{}
+	err := postForm("/oauth/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {Config.RefreshToken},
+		"client_id":     {Config.ClientID},
+	}, token)
+	if err != nil {
+		return nil, err
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("refresh token failed: %s %s", token.Error, token.ErrorDescription)
+	}
+	return token, nil
+}
+
+func deviceGrant() (*oauthToken, error) {
+	code := &deviceCode{}
+	err := postForm("/oauth/authorize_device", url.Values{
+		"client_id": {Config.ClientID},
+		"scope":     {oauthScope},
+	}, code)
+	if err != nil {
+		return nil, err
+	}
+	if code.Error != "" {
+		return nil, fmt.Errorf("device authorization failed: %s %s", code.Error, code.ErrorDescription)
+	}
+
+	verifyURL := code.VerificationURIComplete
+	if verifyURL == "" {
+		verifyURL = code.VerificationURI
+	}
+	utils.PrintlnWithColor(utils.ColorFg("Open "+code.VerificationURI+" and enter the code "+code.UserCode, MainConfig.ThemeColor))
+	_ = utils.OpenBrowser(verifyURL)
+
+	wait := time.Duration(code.Interval) * time.Second
+	if wait <= 0 {
+		wait = interval
+	}
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(wait)
+		token := &oauthToken{}
+		err = postForm("/oauth/token", url.Values{
+			"grant_type":  {deviceGrantType},
+			"device_code": {code.DeviceCode},
+			"client_id":   {Config.ClientID},
+		}, token)
+		if err != nil {
+			return nil, err
+		}
+		switch token.Error {
+		case "":
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			wait += 5 * time.Second
+		default:
+			return nil, fmt.Errorf("device authorization failed: %s %s", token.Error, token.ErrorDescription)
+		}
+	}
+	return nil, errors.New("device authorization expired, please retry")
+}
+
+// postForm post the form to the gitlab oauth endpoint, and decode the json response.
+// oauth errors are returned in the body with status 400, so the body is always decoded
+func postForm(path string, form url.Values, v any) error {
+	resp, err := http.PostForm(strings.TrimSuffix(Config.BaseURL, "/")+path, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s %s: %w", path, resp.Status, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/schollz/progressbar/v3"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// syncProgress report the progress of lab sync, a progress bar on a terminal,
+// the spinner otherwise
+type syncProgress struct {
+	mu       sync.Mutex
+	bar      *progressbar.ProgressBar
+	spinner  *spinner.Spinner
+	total    int
+	groups   int
+	projects int
+}
+
+func newSyncProgress(total int) *syncProgress {
+	p := &syncProgress{total: total}
+	if !utils.IsTTY() {
+		p.spinner = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		p.spinner.Prefix = "sync in process"
+		p.spinner.Start()
+		return p
+	}
+	p.bar = progressbar.NewOptions(total,
+		progressbar.OptionSetDescription(p.description()),
+		progressbar.OptionShowCount(),
+		progressbar.OptionClearOnFinish(),
+	)
+	return p
+}
+
+// done mark one group as synced with n projects
+func (p *syncProgress) done(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.groups++
+	p.projects += n
+	if p.bar != nil {
+		p.bar.Describe(p.description())
+		_ = p.bar.Add(1)
+	}
+}
+
+func (p *syncProgress) finish() {
+	if p.spinner != nil {
+		p.spinner.Stop()
+		return
+	}
+	_ = p.bar.Finish()
+}
+
+func (p *syncProgress) description() string {
+	return fmt.Sprintf("groups: %d/%d, projects: %d", p.groups, p.total, p.projects)
+}
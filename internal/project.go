@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// GetProject return the project of the path with namespace, like Ackerr/lab
+func GetProject(client *gitlab.Client, namespacedPath string) (*gitlab.Project, error) {
+	project, _, err := client.Projects.GetProject(namespacedPath, &gitlab.GetProjectOptions{})
+	return project, err
+}
+
+// OpenMergeRequestCount return the number of the opened merge requests of the project
+func OpenMergeRequestCount(client *gitlab.Client, pid any) (int, error) {
+	opt := &gitlab.ListProjectMergeRequestsOptions{
+		State:       gitlab.Ptr("opened"),
+		ListOptions: gitlab.ListOptions{PerPage: 1, Page: 1},
+	}
+	_, resp, err := client.MergeRequests.ListProjectMergeRequests(pid, opt)
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.TotalItems), nil
+}
+
+// ArchiveProject set the project read-only, the pipelines schedules stop running
+func ArchiveProject(client *gitlab.Client, pid any) error {
+	_, _, err := client.Projects.ArchiveProject(pid)
+	return err
+}
+
+// UnarchiveProject make the archived project writable again
+func UnarchiveProject(client *gitlab.Client, pid any) error {
+	_, _, err := client.Projects.UnarchiveProject(pid)
+	return err
+}
+
+// StarProject star the project, it returns the project with the new star count,
+// starring an already starred project is not an error
+func StarProject(client *gitlab.Client, pid any) (*gitlab.Project, error) {
+	project, resp, err := client.Projects.StarProject(pid)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		project, _, err = client.Projects.GetProject(pid, nil)
+	}
+	return project, err
+}
+
+// UnstarProject unstar the project, it returns the project with the new star count
+func UnstarProject(client *gitlab.Client, pid any) (*gitlab.Project, error) {
+	project, resp, err := client.Projects.UnstarProject(pid)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		project, _, err = client.Projects.GetProject(pid, nil)
+	}
+	return project, err
+}
+
+// StarredProjects return the projects starred by the current user
+func StarredProjects(client *gitlab.Client) ([]*gitlab.Project, error) {
+	opt := &gitlab.ListProjectsOptions{Starred: gitlab.Ptr(true), ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var projects []*gitlab.Project
+	for {
+		ps, resp, err := client.Projects.ListProjects(opt)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, ps...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return projects, nil
+}
+
+// CreateProject create a new project
+func CreateProject(client *gitlab.Client, opts *gitlab.CreateProjectOptions) (*gitlab.Project, error) {
+	project, _, err := client.Projects.CreateProject(opts)
+	return project, err
+}
+
+// NamespaceID return the id of the user or group namespace path
+func NamespaceID(client *gitlab.Client, path string) (int, error) {
+	namespace, _, err := client.Namespaces.GetNamespace(path)
+	if err != nil {
+		return 0, err
+	}
+	return namespace.ID, nil
+}
+
+// DeleteProject delete the project, it can't be undone
+func DeleteProject(client *gitlab.Client, pid any) error {
+	_, err := client.Projects.DeleteProject(pid, nil)
+	return err
+}
+
+// TransferProject move the project into the new namespace, the project url changes
+func TransferProject(client *gitlab.Client, pid any, newNamespace string) (*gitlab.Project, error) {
+	project, _, err := client.Projects.TransferProject(pid, &gitlab.TransferProjectOptions{Namespace: newNamespace})
+	return project, err
+}
+
+// RenameProject change the project name and path, empty ones are kept.
+// A new path changes the project url
+func RenameProject(client *gitlab.Client, pid any, name, path string) (*gitlab.Project, error) {
+	opt := &gitlab.EditProjectOptions{}
+	if name != "" {
+		opt.Name = gitlab.Ptr(name)
+	}
+	if path != "" {
+		opt.Path = gitlab.Ptr(path)
+	}
+	project, _, err := client.Projects.EditProject(pid, opt)
+	return project, err
+}
+
+// ForkProject fork the project into the target namespace, empty means the namespace of the user
+func ForkProject(client *gitlab.Client, pid any, targetNamespace string) (*gitlab.Project, error) {
+	opt := &gitlab.ForkProjectOptions{}
+	if targetNamespace != "" {
+		opt.NamespacePath = gitlab.Ptr(targetNamespace)
+	}
+	fork, _, err := client.Projects.ForkProject(pid, opt)
+	return fork, err
+}
+
+// ExistingFork return the fork of the project in the target namespace, nil if not forked yet.
+// Empty targetNamespace means the namespace of the user. It returns an error if the path of
+// the fork is taken by a project which isn't a fork of the project
+func ExistingFork(client *gitlab.Client, project *gitlab.Project, targetNamespace string) (*gitlab.Project, error) {
+	if targetNamespace == "" {
+		user, _, err := client.Users.CurrentUser()
+		if err != nil {
+			return nil, err
+		}
+		targetNamespace = user.Username
+	}
+	fork, resp, err := client.Projects.GetProject(targetNamespace+"/"+project.Path, &gitlab.GetProjectOptions{})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if fork.ForkedFromProject == nil || fork.ForkedFromProject.ID != project.ID {
+		return nil, fmt.Errorf("%s is taken by a project that is not a fork of %s", fork.PathWithNamespace, project.PathWithNamespace)
+	}
+	return fork, nil
+}
@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// pure go sqlite driver, lab is built without cgo
+	_ "modernc.org/sqlite"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ProjectInfo a synced project with its metadata
+type ProjectInfo struct {
+	Path      string   `json:"path"`
+	StarCount int      `json:"star_count"`
+	Topics    []string `json:"topics"`
+}
+
+// ProjectFilter filter the synced projects, the zero value matches all projects
+type ProjectFilter struct {
+	// Search the substring of the project path, case-insensitive
+	Search string
+	// Topic only the projects with the topic
+	Topic string
+	// MinStars only the projects with at least MinStars stars
+	MinStars int
+}
+
+// the projects of each gitlab (base_url) are stored separately, position keeps the order of lab sync.
+// topics are stored as ,topic1,topic2, so a single topic is matched with like.
+// imports records the gitlabs whose projects file was imported
+const projectSchema = `
+CREATE TABLE IF NOT EXISTS projects (
+	base_url       TEXT    NOT NULL,
+	path           TEXT    NOT NULL,
+	position       INTEGER NOT NULL,
+	star_count     INTEGER NOT NULL DEFAULT 0,
+	topics         TEXT    NOT NULL DEFAULT '',
+	last_cloned_at TIMESTAMP,
+	PRIMARY KEY (base_url, path)
+);
+CREATE TABLE IF NOT EXISTS imports (
+	base_url    TEXT PRIMARY KEY,
+	imported_at TIMESTAMP NOT NULL
+)`
+
+func projectDBPath() string {
+	return filepath.Join(LabDir, "projects.db")
+}
+
+// OpenProjectDB open the projects database, it is created if missing. The projects file of the
+// older versions is imported once per gitlab
+func OpenProjectDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite", projectDBPath())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = db.Exec(projectSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err = importProjectsFile(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// importProjectsFile import the flat projects file, one project path per line, unless it was
+// imported already. A database synced before the imports were recorded is kept as is
+func importProjectsFile(db *sql.DB) error {
+	var imported, count int
+	if err := db.QueryRow(`SELECT count(*) FROM imports WHERE base_url = ?`, Config.BaseURL).Scan(&imported); err != nil {
+		return err
+	}
+	if imported > 0 {
+		return nil
+	}
+	if err := db.QueryRow(`SELECT count(*) FROM projects WHERE base_url = ?`, Config.BaseURL).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 && utils.FileExists(ProjectPath) {
+		buf, err := os.ReadFile(ProjectPath)
+		if err != nil {
+			return err
+		}
+		var projects []ProjectInfo
+		for _, path := range strings.Fields(string(buf)) {
+			projects = append(projects, ProjectInfo{Path: path})
+		}
+		if err = SaveProjects(db, projects); err != nil {
+			return err
+		}
+	}
+	_, err := db.Exec(`INSERT OR IGNORE INTO imports (base_url, imported_at) VALUES (?, ?)`,
+		Config.BaseURL, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// likeEscaper escape the wildcards of a like pattern, the escape character is \
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// QueryProjects return the paths of the synced projects matching the filter, in the order of lab sync
+func QueryProjects(db *sql.DB, filter ProjectFilter) []string {
+	query := `SELECT path FROM projects WHERE base_url = ? AND star_count >= ?`
+	args := []any{Config.BaseURL, filter.MinStars}
+	if filter.Search != "" {
+		query += ` AND path LIKE ? ESCAPE '\'`
+		args = append(args, "%"+likeEscaper.Replace(filter.Search)+"%")
+	}
+	if filter.Topic != "" {
+		query += ` AND topics LIKE ? ESCAPE '\'`
+		args = append(args, "%,"+likeEscaper.Replace(filter.Topic)+",%")
+	}
+	rows, err := db.Query(query+` ORDER BY position`, args...)
+	utils.Check(err)
+	defer rows.Close()
+
+	var projects []string
+	for rows.Next() {
+		var path string
+		utils.Check(rows.Scan(&path))
+		projects = append(projects, path)
+	}
+	utils.Check(rows.Err())
+	return projects
+}
+
+// SaveProjects replace the synced projects of the gitlab, the clone time of the kept projects is not changed
+func SaveProjects(db *sql.DB, projects []ProjectInfo) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.Exec(`CREATE TEMP TABLE synced (path TEXT PRIMARY KEY)`); err != nil {
+		return err
+	}
+	for i, p := range projects {
+		if p.Path == "" {
+			continue
+		}
+		topics := ""
+		if len(p.Topics) > 0 {
+			topics = "," + strings.Join(p.Topics, ",") + ","
+		}
+		_, err = tx.Exec(`
+			INSERT INTO projects (base_url, path, position, star_count, topics) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (base_url, path) DO UPDATE SET
+				position = excluded.position, star_count = excluded.star_count, topics = excluded.topics`,
+			Config.BaseURL, p.Path, i, p.StarCount, topics)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(`INSERT OR IGNORE INTO synced (path) VALUES (?)`, p.Path); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`DELETE FROM projects WHERE base_url = ? AND path NOT IN (SELECT path FROM synced)`, Config.BaseURL)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DROP TABLE synced`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkCloned save the clone time of the project
+func MarkCloned(db *sql.DB, path string) error {
+	_, err := db.Exec(`UPDATE projects SET last_cloned_at = ? WHERE base_url = ? AND path = ?`, time.Now().UTC().Format(time.RFC3339), Config.BaseURL, path)
+	return err
+}
+
+// SyncedProjects return the synced projects matching the filter, exit if lab sync never run
+func SyncedProjects(filter ProjectFilter) []string {
+	db, err := OpenProjectDB()
+	utils.Check(err)
+	defer db.Close()
+	if Config.ProjectsURL != "" {
+		// an unreachable projects_url falls back to the projects already saved, the error goes
+		// to stderr so the stdout of lab cs stays the path to cd
+		if err = RefreshURLProjects(db); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	projects := QueryProjects(db, filter)
+	if len(projects) == 0 && filter == (ProjectFilter{}) {
+		utils.Err("no synced projects, please run `lab sync` first")
+	}
+	return projects
+}
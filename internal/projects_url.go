@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ackerr/lab/utils"
+)
+
+const (
+	// projectsURLTTL how long the projects of projects_url are reused if cache_ttl is not set
+	projectsURLTTL = time.Hour
+	// projectsURLRefreshTimeout limit the refresh before a command, the saved projects are used on timeout
+	projectsURLRefreshTimeout = 5 * time.Second
+	// projectsURLRetry is the wait after a failed refresh before the next one
+	projectsURLRetry = 10 * time.Minute
+)
+
+// refreshFailedPath is the marker of the last failed refresh, it holds the projects_url
+func refreshFailedPath() string {
+	return filepath.Join(LabDir, "projects_url_failed")
+}
+
+// refreshFailedRecently check if the refresh of the current projects_url failed less than projectsURLRetry ago
+func refreshFailedRecently() bool {
+	info, err := os.Stat(refreshFailedPath())
+	if err != nil || time.Since(info.ModTime()) > projectsURLRetry {
+		return false
+	}
+	buf, err := os.ReadFile(refreshFailedPath())
+	return err == nil && string(buf) == Config.ProjectsURL
+}
+
+func projectsURLKey() string {
+	return "projects_url=" + Config.ProjectsURL
+}
+
+// fetchProjectsURL get the projects file of projects_url, one project path per line like the projects file,
+// the blank lines and the # comments are skipped
+func fetchProjectsURL(ctx context.Context) ([]ProjectInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, Config.ProjectsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get projects_url %s: %s", Config.ProjectsURL, resp.Status)
+	}
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var projects []ProjectInfo
+	for _, line := range strings.Split(string(buf), "\n") {
+		path := strings.TrimSpace(line)
+		if path == "" || strings.HasPrefix(path, "#") {
+			continue
+		}
+		projects = append(projects, ProjectInfo{Path: path})
+	}
+	return projects, nil
+}
+
+// urlProjects return the projects of projects_url, the cached ones are used if younger
+// than cache_ttl, or projectsURLTTL without cache_ttl, unless force
+func urlProjects(ctx context.Context, force bool) ([]ProjectInfo, error) {
+	ttl := cacheTTL()
+	if ttl <= 0 {
+		ttl = projectsURLTTL
+	}
+	if !force {
+		if projects, ok := readProjectCache(ttl, projectsURLKey()); ok {
+			return projects, nil
+		}
+	}
+	projects, err := fetchProjectsURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	utils.PrintErr(writeProjectCache(projects, projectsURLKey()))
+	return projects, nil
+}
+
+// RefreshURLProjects save the projects of projects_url into db when the cached ones expired,
+// so the team members don't need to run lab sync. On error, db keeps the projects saved before,
+// and the refresh is skipped for projectsURLRetry, so an unreachable host doesn't slow every command
+func RefreshURLProjects(db *sql.DB) error {
+	ttl := cacheTTL()
+	if ttl <= 0 {
+		ttl = projectsURLTTL
+	}
+	if _, ok := readProjectCache(ttl, projectsURLKey()); ok || refreshFailedRecently() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), projectsURLRefreshTimeout)
+	defer cancel()
+	projects, err := urlProjects(ctx, true)
+	if err != nil {
+		_ = os.WriteFile(refreshFailedPath(), []byte(Config.ProjectsURL), utils.FilePerm)
+		return err
+	}
+	_ = os.Remove(refreshFailedPath())
+	return SaveProjects(db, projects)
+}
@@ -0,0 +1,19 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// ProtectBranch protect the branch or the wildcard branch pattern, like release/*
+func ProtectBranch(client *gitlab.Client, pid any, branch string, opts *gitlab.ProtectRepositoryBranchesOptions) (*gitlab.ProtectedBranch, error) {
+	opts.Name = gitlab.Ptr(branch)
+	protected, _, err := client.ProtectedBranches.ProtectRepositoryBranches(pid, opts)
+	return protected, err
+}
+
+// ProtectTag protect the tag or the wildcard tag pattern, like v*
+func ProtectTag(client *gitlab.Client, pid any, tag string, opts *gitlab.ProtectRepositoryTagsOptions) (*gitlab.ProtectedTag, error) {
+	opts.Name = gitlab.Ptr(tag)
+	protected, _, err := client.ProtectedTags.ProtectRepositoryTags(pid, opts)
+	return protected, err
+}
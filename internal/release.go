@@ -0,0 +1,30 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListReleases return the releases of the project, the latest first
+func ListReleases(client *gitlab.Client, pid any) []*gitlab.Release {
+	opt := &gitlab.ListReleasesOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var releases []*gitlab.Release
+	for {
+		rs, resp, err := client.Releases.ListReleases(pid, opt)
+		utils.Check(err)
+		releases = append(releases, rs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return releases
+}
+
+// CreateRelease create a release of the project, the tag is created if missing
+func CreateRelease(client *gitlab.Client, pid any, opts *gitlab.CreateReleaseOptions) (*gitlab.Release, error) {
+	release, _, err := client.Releases.CreateRelease(pid, opts)
+	return release, err
+}
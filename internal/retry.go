@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+const (
+	maxAttempts = 5
+	maxBackoff  = 60 * time.Second
+)
+
+// exponentialBackoff return the wait before the next attempt, 1s, 2s, 4s... at most maxBackoff
+func exponentialBackoff(attempt int) time.Duration {
+	if attempt >= 6 {
+		return maxBackoff
+	}
+	return min(time.Second<<attempt, maxBackoff)
+}
+
+// retryAfter parse the Retry-After header of the response, in seconds or a http date
+func retryAfter(resp *gitlab.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// withRetry call the api again when gitlab responds 429 too many requests, at most maxAttempts times.
+// It waits the Retry-After of the response if present, the exponential backoff otherwise,
+// and stops waiting when ctx is canceled
+func withRetry(ctx context.Context, call func() (*gitlab.Response, error)) error {
+	for attempt := 0; ; attempt++ {
+		resp, err := call()
+		if err == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests || attempt+1 >= maxAttempts {
+			return err
+		}
+		wait := exponentialBackoff(attempt)
+		if d, ok := retryAfter(resp); ok {
+			wait = min(max(d, 0), maxBackoff)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+func TestWithRetryTooManyRequests(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version": "17.0.0"}`))
+	}))
+	defer srv.Close()
+	// without the retries of the client, so withRetry sees the 429 responses
+	client, err := gitlab.NewClient("", gitlab.WithBaseURL(srv.URL+"/api/v4"), gitlab.WithoutRetries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var version *gitlab.Version
+	err = withRetry(context.Background(), func() (*gitlab.Response, error) {
+		var resp *gitlab.Response
+		version, resp, err = client.Version.GetVersion()
+		return resp, err
+	})
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if version == nil || version.Version != "17.0.0" {
+		t.Errorf("version = %+v, want 17.0.0", version)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{5, 32 * time.Second},
+		{6, maxBackoff},
+		{100, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := exponentialBackoff(tt.attempt); got != tt.want {
+			t.Errorf("exponentialBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	response := func(value string) *gitlab.Response {
+		resp := &http.Response{Header: http.Header{}}
+		if value != "" {
+			resp.Header.Set("Retry-After", value)
+		}
+		return &gitlab.Response{Response: resp}
+	}
+
+	if d, ok := retryAfter(response("7")); !ok || d != 7*time.Second {
+		t.Errorf("retryAfter(7) = %s, %t, want 7s", d, ok)
+	}
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	if d, ok := retryAfter(response(date)); !ok || d < 28*time.Second || d > 30*time.Second {
+		t.Errorf("retryAfter(%s) = %s, %t, want about 30s", date, d, ok)
+	}
+	for _, value := range []string{"", "soon"} {
+		if d, ok := retryAfter(response(value)); ok {
+			t.Errorf("retryAfter(%q) = %s, want no value", value, d)
+		}
+	}
+}
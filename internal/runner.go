@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListRunners return the runners of the scope, project and group list the runners available
+// to the project or group id, all lists every runner of the instance and needs an admin token.
+// Only the runners with all the tags are returned, empty tags means all
+func ListRunners(client *gitlab.Client, scope string, id any, tags []string) []*gitlab.Runner {
+	opt := gitlab.ListRunnersOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	if len(tags) > 0 {
+		opt.TagList = &tags
+	}
+
+	var runners []*gitlab.Runner
+	for {
+		var rs []*gitlab.Runner
+		var resp *gitlab.Response
+		var err error
+		switch scope {
+		case "project":
+			projectOpt := gitlab.ListProjectRunnersOptions(opt)
+			rs, resp, err = client.Runners.ListProjectRunners(id, &projectOpt)
+		case "group":
+			groupOpt := gitlab.ListGroupsRunnersOptions{ListOptions: opt.ListOptions, TagList: opt.TagList}
+			rs, resp, err = client.Runners.ListGroupsRunners(id, &groupOpt)
+		case "all":
+			rs, resp, err = client.Runners.ListAllRunners(&opt)
+		default:
+			utils.Err(fmt.Sprintf("invalid runner scope %s, use project, group or all", scope))
+		}
+		utils.Check(err)
+		runners = append(runners, rs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return runners
+}
+
+// RunnerTags return the tags of each runner id, the list api has no tags, so the details
+// of at most num_workers runners are requested in parallel
+func RunnerTags(client *gitlab.Client, runners []*gitlab.Runner) map[int][]string {
+	tags := make(map[int][]string, len(runners))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(MainConfig.NumWorkers, 1))
+	for _, r := range runners {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			details, _, err := client.Runners.GetRunnerDetails(id)
+			if err != nil {
+				utils.PrintErr(err)
+				return
+			}
+			mu.Lock()
+			tags[id] = details.TagList
+			mu.Unlock()
+		}(r.ID)
+	}
+	wg.Wait()
+	return tags
+}
+
+// PauseRunner pause the runner, it picks no new jobs
+func PauseRunner(client *gitlab.Client, runnerID int) error {
+	_, _, err := client.Runners.UpdateRunnerDetails(runnerID, &gitlab.UpdateRunnerDetailsOptions{Paused: gitlab.Ptr(true)})
+	return err
+}
+
+// ResumeRunner resume the paused runner
+func ResumeRunner(client *gitlab.Client, runnerID int) error {
+	_, _, err := client.Runners.UpdateRunnerDetails(runnerID, &gitlab.UpdateRunnerDetailsOptions{Paused: gitlab.Ptr(false)})
+	return err
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListPipelineSchedules return the pipeline schedules of the project
+func ListPipelineSchedules(client *gitlab.Client, pid any) []*gitlab.PipelineSchedule {
+	opt := &gitlab.ListPipelineSchedulesOptions{PerPage: perPage, Page: 1}
+
+	var schedules []*gitlab.PipelineSchedule
+	for {
+		ss, resp, err := client.PipelineSchedules.ListPipelineSchedules(pid, opt)
+		utils.Check(err)
+		schedules = append(schedules, ss...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return schedules
+}
+
+// RunPipelineSchedule run the pipeline of the schedule now, the next scheduled run is kept
+func RunPipelineSchedule(client *gitlab.Client, pid any, scheduleID int) error {
+	_, err := client.PipelineSchedules.RunPipelineSchedule(pid, scheduleID)
+	return err
+}
+
+// CreatePipelineSchedule create an active pipeline schedule of the ref, cron is like 0 * * * *,
+// empty timezone uses the gitlab default UTC
+func CreatePipelineSchedule(client *gitlab.Client, pid any, description, ref, cron, timezone string) (*gitlab.PipelineSchedule, error) {
+	opt := &gitlab.CreatePipelineScheduleOptions{
+		Description: gitlab.Ptr(description),
+		Ref:         gitlab.Ptr(ref),
+		Cron:        gitlab.Ptr(cron),
+		Active:      gitlab.Ptr(true),
+	}
+	if timezone != "" {
+		opt.CronTimezone = gitlab.Ptr(timezone)
+	}
+	schedule, _, err := client.PipelineSchedules.CreatePipelineSchedule(pid, opt)
+	return schedule, err
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// maxSearchResults the search stops paging after that many results
+const maxSearchResults = 500
+
+// SearchScopes the scopes supported by GlobalSearch and ProjectSearch, projects is global only
+var SearchScopes = []string{"projects", "blobs", "commits", "issues", "merge_requests"}
+
+// GlobalSearch search the scope in the whole gitlab instance, the result is a slice of
+// *gitlab.Project, *gitlab.Blob, *gitlab.Commit, *gitlab.Issue or *gitlab.MergeRequest
+func GlobalSearch(client *gitlab.Client, query, scope string) any {
+	switch scope {
+	case "projects":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+			return client.Search.Projects(query, opt)
+		})
+	case "blobs":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Blob, *gitlab.Response, error) {
+			return client.Search.Blobs(query, opt)
+		})
+	case "commits":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Commit, *gitlab.Response, error) {
+			return client.Search.Commits(query, opt)
+		})
+	case "issues":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+			return client.Search.Issues(query, opt)
+		})
+	case "merge_requests":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+			return client.Search.MergeRequests(query, opt)
+		})
+	}
+	utils.Err(fmt.Sprintf("invalid scope %s, use projects, blobs, commits, issues or merge_requests", scope))
+	return nil
+}
+
+// ProjectSearch search the scope in the project, like GlobalSearch
+func ProjectSearch(client *gitlab.Client, pid any, query, scope string) any {
+	switch scope {
+	case "blobs":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Blob, *gitlab.Response, error) {
+			return client.Search.BlobsByProject(pid, query, opt)
+		})
+	case "commits":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Commit, *gitlab.Response, error) {
+			return client.Search.CommitsByProject(pid, query, opt)
+		})
+	case "issues":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+			return client.Search.IssuesByProject(pid, query, opt)
+		})
+	case "merge_requests":
+		return searchPages(func(opt *gitlab.SearchOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+			return client.Search.MergeRequestsByProject(pid, query, opt)
+		})
+	}
+	utils.Err(fmt.Sprintf("invalid scope %s in a project, use blobs, commits, issues or merge_requests", scope))
+	return nil
+}
+
+// searchPages call the search page by page, at most maxSearchResults results
+func searchPages[T any](search func(opt *gitlab.SearchOptions) ([]T, *gitlab.Response, error)) []T {
+	opt := &gitlab.SearchOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+
+	var results []T
+	for {
+		rs, resp, err := search(opt)
+		utils.Check(err)
+		results = append(results, rs...)
+		if len(results) >= maxSearchResults {
+			return results[:maxSearchResults]
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return results
+}
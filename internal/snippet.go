@@ -0,0 +1,75 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// CreateSnippet create a personal snippet with a single file, visibility is private, internal or public
+func CreateSnippet(client *gitlab.Client, title, filename, content, visibility string) (*gitlab.Snippet, error) {
+	snippet, _, err := client.Snippets.CreateSnippet(&gitlab.CreateSnippetOptions{
+		Title:      gitlab.Ptr(title),
+		FileName:   gitlab.Ptr(filename),
+		Content:    gitlab.Ptr(content),
+		Visibility: gitlab.Ptr(gitlab.VisibilityValue(visibility)),
+	})
+	return snippet, err
+}
+
+// CreateProjectSnippet create a snippet of the project with a single file
+func CreateProjectSnippet(client *gitlab.Client, pid any, title, filename, content, visibility string) (*gitlab.Snippet, error) {
+	snippet, _, err := client.ProjectSnippets.CreateSnippet(pid, &gitlab.CreateProjectSnippetOptions{
+		Title:      gitlab.Ptr(title),
+		FileName:   gitlab.Ptr(filename),
+		Content:    gitlab.Ptr(content),
+		Visibility: gitlab.Ptr(gitlab.VisibilityValue(visibility)),
+	})
+	return snippet, err
+}
+
+// ListSnippets return the personal snippets of the user
+func ListSnippets(client *gitlab.Client) []*gitlab.Snippet {
+	opt := &gitlab.ListSnippetsOptions{PerPage: perPage, Page: 1}
+
+	var snippets []*gitlab.Snippet
+	for {
+		ss, resp, err := client.Snippets.ListSnippets(opt)
+		utils.Check(err)
+		snippets = append(snippets, ss...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return snippets
+}
+
+// ListProjectSnippets return the snippets of the project
+func ListProjectSnippets(client *gitlab.Client, pid any) []*gitlab.Snippet {
+	opt := &gitlab.ListProjectSnippetsOptions{PerPage: perPage, Page: 1}
+
+	var snippets []*gitlab.Snippet
+	for {
+		ss, resp, err := client.ProjectSnippets.ListSnippets(pid, opt)
+		utils.Check(err)
+		snippets = append(snippets, ss...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return snippets
+}
+
+// SnippetContent return the raw content of the snippet, empty project means a personal snippet
+func SnippetContent(client *gitlab.Client, project string, snippetID int) (string, error) {
+	var content []byte
+	var err error
+	if project != "" {
+		content, _, err = client.ProjectSnippets.SnippetContent(project, snippetID)
+	} else {
+		content, _, err = client.Snippets.SnippetContent(snippetID)
+	}
+	return string(content), err
+}
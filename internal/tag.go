@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"sync"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListTags return the tags of the project matching the search, empty search means all
+func ListTags(client *gitlab.Client, pid any, search string) []*gitlab.Tag {
+	opt := &gitlab.ListTagsOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}}
+	if search != "" {
+		opt.Search = gitlab.Ptr(search)
+	}
+
+	var tags []*gitlab.Tag
+	for {
+		ts, resp, err := client.Tags.ListTags(pid, opt)
+		utils.Check(err)
+		tags = append(tags, ts...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return tags
+}
+
+// TagPipelines return the status of the latest pipeline of each tag by name, fetched with
+// num_workers requests at a time. The tags without a pipeline are missing
+func TagPipelines(client *gitlab.Client, pid any, tags []*gitlab.Tag) map[string]string {
+	statuses := make(map[string]string, len(tags))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(MainConfig.NumWorkers, 1))
+	for _, t := range tags {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pipeline, err := LatestPipeline(client, pid, name)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			statuses[name] = pipeline.Status
+			mu.Unlock()
+		}(t.Name)
+	}
+	wg.Wait()
+	return statuses
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"strconv"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// GetTokenInfo return the personal access token used by the client, needs gitlab 15.5 or later
+func GetTokenInfo(client *gitlab.Client) (*gitlab.PersonalAccessToken, error) {
+	token, _, err := client.PersonalAccessTokens.GetSinglePersonalAccessToken()
+	return token, err
+}
+
+// GetRateLimit return the rate limit of the RateLimit-* response headers, reset is
+// a unix timestamp. All of them are zero when gitlab doesn't send the headers
+func GetRateLimit(client *gitlab.Client) (limit, remaining, reset int, err error) {
+	_, resp, err := client.Users.CurrentUser()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	limit, _ = strconv.Atoi(resp.Header.Get("RateLimit-Limit"))
+	remaining, _ = strconv.Atoi(resp.Header.Get("RateLimit-Remaining"))
+	reset, _ = strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+	return limit, remaining, reset, nil
+}
+
+// Ping request the gitlab version, it returns the response time of the request
+func Ping(client *gitlab.Client) (time.Duration, error) {
+	start := time.Now()
+	_, _, err := client.Version.GetVersion()
+	return time.Since(start), err
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// userID return the id of the username
+func userID(client *gitlab.Client, username string) (int, error) {
+	users, _, err := client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.Ptr(username)})
+	if err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("user %s not found", username)
+	}
+	return users[0].ID, nil
+}
+
+// GetUser return the user of the username, the email is only returned for the current user or admins
+func GetUser(client *gitlab.Client, username string) (*gitlab.User, error) {
+	id, err := userID(client, username)
+	if err != nil {
+		return nil, err
+	}
+	user, _, err := client.Users.GetUser(id, gitlab.GetUsersOptions{})
+	return user, err
+}
+
+// UserGroups return the group memberships of the user, only allowed for admins
+func UserGroups(client *gitlab.Client, uid int) ([]*gitlab.UserMembership, error) {
+	opt := &gitlab.GetUserMembershipOptions{ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1}, Type: gitlab.Ptr("Namespace")}
+
+	var memberships []*gitlab.UserMembership
+	for {
+		ms, resp, err := client.Users.GetUserMemberships(uid, opt)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, ms...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return memberships, nil
+}
+
+// SearchUsers return the first page of the active users matching the name, username or public email
+func SearchUsers(client *gitlab.Client, query string) []*gitlab.User {
+	users, _, err := client.Users.ListUsers(&gitlab.ListUsersOptions{
+		ListOptions: gitlab.ListOptions{PerPage: perPage, Page: 1},
+		Search:      gitlab.Ptr(query),
+		Active:      gitlab.Ptr(true),
+	})
+	utils.Check(err)
+	return users
+}
+
+// UserIDs return the ids of the usernames, me adds the current user
+func UserIDs(client *gitlab.Client, usernames []string, me bool) ([]int, error) {
+	ids := make([]int, 0, len(usernames)+1)
+	if me {
+		user, _, err := client.Users.CurrentUser()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, user.ID)
+	}
+	for _, username := range usernames {
+		id, err := userID(client, strings.TrimPrefix(username, "@"))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
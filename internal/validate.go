@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// ValidateConfig check the base url resolves, the token is valid and has the api scope.
+// The result of each check is printed
+func ValidateConfig(cfg *gitlabConfig) error {
+	u, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base_url %s: %w", cfg.BaseURL, err)
+	}
+	addrs, err := net.LookupHost(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("base_url %s doesn't resolve: %w", cfg.BaseURL, err)
+	}
+	fmt.Printf("%s resolves to %s\n", u.Hostname(), strings.Join(addrs, ", "))
+
+	newClient := gitlab.NewClient
+	if cfg.ClientID != "" {
+		newClient = gitlab.NewOAuthClient
+	}
+	client, err := newClient(cfg.Token, gitlab.WithBaseURL(strings.Join([]string{cfg.BaseURL, "api", apiVersion}, "/")))
+	if err != nil {
+		return err
+	}
+	user, _, err := client.Users.CurrentUser()
+	if err != nil {
+		return fmt.Errorf("token is invalid: %w", err)
+	}
+	fmt.Printf("authenticated as %s (@%s)\n", user.Name, user.Username)
+
+	// the oauth token is always requested with the api scope
+	if cfg.ClientID != "" {
+		fmt.Println("oauth token has scope", oauthScope)
+		return nil
+	}
+	token, resp, err := client.PersonalAccessTokens.GetSinglePersonalAccessToken()
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			fmt.Println("token scopes can't be checked, gitlab is older than 15.5")
+			return nil
+		}
+		return err
+	}
+	fmt.Println("token scopes:", strings.Join(token.Scopes, ", "))
+	if !slices.Contains(token.Scopes, "api") {
+		return errors.New("token doesn't have the api scope, lab needs it to work")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"net/http"
+	"regexp"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListProjectVariables return the ci/cd variables of the project
+func ListProjectVariables(client *gitlab.Client, pid any) []*gitlab.ProjectVariable {
+	opt := &gitlab.ListProjectVariablesOptions{PerPage: perPage, Page: 1}
+
+	var variables []*gitlab.ProjectVariable
+	for {
+		vs, resp, err := client.ProjectVariables.ListVariables(pid, opt)
+		utils.Check(err)
+		variables = append(variables, vs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return variables
+}
+
+// ListGroupVariables return the ci/cd variables of the group, the variables of the parent groups are not included
+func ListGroupVariables(client *gitlab.Client, gid any) []*gitlab.GroupVariable {
+	opt := &gitlab.ListGroupVariablesOptions{PerPage: perPage, Page: 1}
+
+	var variables []*gitlab.GroupVariable
+	for {
+		vs, resp, err := client.GroupVariables.ListVariables(gid, opt)
+		utils.Check(err)
+		variables = append(variables, vs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return variables
+}
+
+// SetProjectVariable update the ci/cd variable of the project, create it if missing.
+// varType is env_var or file
+func SetProjectVariable(client *gitlab.Client, pid any, key, value, varType string, protected, masked bool) error {
+	_, resp, err := client.ProjectVariables.UpdateVariable(pid, key, &gitlab.UpdateProjectVariableOptions{
+		Value:        gitlab.Ptr(value),
+		VariableType: gitlab.Ptr(gitlab.VariableTypeValue(varType)),
+		Protected:    gitlab.Ptr(protected),
+		Masked:       gitlab.Ptr(masked),
+	})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		return err
+	}
+	_, _, err = client.ProjectVariables.CreateVariable(pid, &gitlab.CreateProjectVariableOptions{
+		Key:          gitlab.Ptr(key),
+		Value:        gitlab.Ptr(value),
+		VariableType: gitlab.Ptr(gitlab.VariableTypeValue(varType)),
+		Protected:    gitlab.Ptr(protected),
+		Masked:       gitlab.Ptr(masked),
+	})
+	return err
+}
+
+// DeleteProjectVariable delete the ci/cd variable of the project
+func DeleteProjectVariable(client *gitlab.Client, pid any, key string) error {
+	_, err := client.ProjectVariables.RemoveVariable(pid, key, nil)
+	return err
+}
+
+// maskableValue the gitlab masking rules, a single line of at least 8 characters
+// from the base64 alphabet, @, :, ., ~ and -
+var maskableValue = regexp.MustCompile(`^[A-Za-z0-9+/=@:.~_-]{8,}$`)
+
+// Maskable check if gitlab accepts the value of a masked variable
+func Maskable(value string) bool {
+	return maskableValue.MatchString(value)
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// releaseURL the github api of the latest lab release
+const releaseURL = "https://api.github.com/repos/ackerr/lab/releases/latest"
+
+// LatestRelease return the tag of the latest lab release on github, like v0.4.3
+func LatestRelease() (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(releaseURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get the latest release: %s", resp.Status)
+	}
+	release := struct {
+		TagName string `json:"tag_name"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
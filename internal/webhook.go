@@ -0,0 +1,52 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// WebhookOptions the events triggering the webhook and its security settings,
+// a nil event keeps the default of gitlab, only the push events are enabled
+type WebhookOptions struct {
+	PushEvents            *bool
+	TagPushEvents         *bool
+	MergeRequestsEvents   *bool
+	PipelineEvents        *bool
+	Secret                string
+	EnableSSLVerification bool
+}
+
+// ListWebhooks return the webhooks of the project
+func ListWebhooks(client *gitlab.Client, pid any) []*gitlab.ProjectHook {
+	opt := &gitlab.ListProjectHooksOptions{PerPage: perPage, Page: 1}
+
+	var hooks []*gitlab.ProjectHook
+	for {
+		hs, resp, err := client.Projects.ListProjectHooks(pid, opt)
+		utils.Check(err)
+		hooks = append(hooks, hs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return hooks
+}
+
+// CreateWebhook add a webhook to the project
+func CreateWebhook(client *gitlab.Client, pid any, url string, opts WebhookOptions) (*gitlab.ProjectHook, error) {
+	hookOpts := &gitlab.AddProjectHookOptions{
+		URL:                   gitlab.Ptr(url),
+		PushEvents:            opts.PushEvents,
+		TagPushEvents:         opts.TagPushEvents,
+		MergeRequestsEvents:   opts.MergeRequestsEvents,
+		PipelineEvents:        opts.PipelineEvents,
+		EnableSSLVerification: gitlab.Ptr(opts.EnableSSLVerification),
+	}
+	if opts.Secret != "" {
+		hookOpts.Token = gitlab.Ptr(opts.Secret)
+	}
+	hook, _, err := client.Projects.AddProjectHook(pid, hookOpts)
+	return hook, err
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	"github.com/ackerr/lab/utils"
+)
+
+// ListWikiPages return the wiki pages of the project with their content
+func ListWikiPages(client *gitlab.Client, pid any) []*gitlab.Wiki {
+	pages, _, err := client.Wikis.ListWikis(pid, &gitlab.ListWikisOptions{WithContent: gitlab.Ptr(true)})
+	utils.Check(err)
+	return pages
+}
+
+// GetWikiPage return the wiki page of the slug
+func GetWikiPage(client *gitlab.Client, pid any, slug string) (*gitlab.Wiki, error) {
+	page, _, err := client.Wikis.GetWikiPage(pid, slug, &gitlab.GetWikiPageOptions{})
+	return page, err
+}
+
+// RenderMarkdown render the gitlab flavored markdown to html, the references are resolved in the project
+func RenderMarkdown(client *gitlab.Client, project, text string) (string, error) {
+	md, _, err := client.Markdown.Render(&gitlab.RenderOptions{
+		Text:                    gitlab.Ptr(text),
+		GitlabFlavouredMarkdown: gitlab.Ptr(true),
+		Project:                 gitlab.Ptr(project),
+	})
+	if err != nil {
+		return "", err
+	}
+	return md.HTML, nil
+}
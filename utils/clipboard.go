@@ -0,0 +1,8 @@
+package utils
+
+import "github.com/atotto/clipboard"
+
+// CopyToClipboard copy the text to the system clipboard, on linux xclip, xsel or wl-copy is needed
+func CopyToClipboard(text string) error {
+	return clipboard.WriteAll(text)
+}
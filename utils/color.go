@@ -30,7 +30,50 @@ func PrintlnWithColor(a ...interface{}) {
 func RandomColor(in string) string {
 	rand.Seed(time.Now().UnixNano())
 	index := rand.Intn(len(Color))
-	return color.New(Color[index]).Sprintf(in)
+	return color.New(Color[index]).Sprint(in)
+}
+
+// IndexColor color the string by the index, the same index always get the same color
+func IndexColor(in string, i int) string {
+	return color.New(Color[i%len(Color)]).Sprint(in)
+}
+
+// StatusColor color the gitlab pipeline or job status,
+// green=passed, red=failed, yellow=running
+func StatusColor(status string) string {
+	return color.New(statusAttr(status)).Sprint(status)
+}
+
+// StatusIcon return the colored icon of the gitlab pipeline or job status
+func StatusIcon(status string) string {
+	icon := "○"
+	switch status {
+	case "success", "passed":
+		icon = "✔"
+	case "failed":
+		icon = "✘"
+	case "running":
+		icon = "●"
+	case "canceled":
+		icon = "⊘"
+	case "skipped":
+		icon = "»"
+	case "manual":
+		icon = "▶"
+	}
+	return color.New(statusAttr(status)).Sprint(icon)
+}
+
+func statusAttr(status string) color.Attribute {
+	switch status {
+	case "success", "passed", "online":
+		return color.FgGreen
+	case "failed", "offline", "stale":
+		return color.FgRed
+	case "running", "pending", "created", "preparing", "waiting_for_resource", "paused":
+		return color.FgYellow
+	}
+	return color.FgWhite
 }
 
 func ColorFg(val, color string) string {
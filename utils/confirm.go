@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Confirm ask the question on the terminal, return true only if the answer is y or yes.
+// The prompt is written to stderr, so it doesn't mix with the json or yaml output
+func Confirm(prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt+" [y/N] ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// ConfirmInput ask the user to type the expected text, return true only if the answer matches
+func ConfirmInput(prompt, expected string) bool {
+	fmt.Fprintf(os.Stderr, "%s\nType %s to confirm: ", prompt, expected)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(answer) == expected
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// maxWordDiff limit the size of the lcs table, bigger changes are shown as removed and added
+const maxWordDiff = 1 << 20
+
+var (
+	removedWord = color.New(color.FgRed)
+	addedWord   = color.New(color.FgGreen)
+)
+
+// WordDiff return the words of before and after merged, the removed words in red and the added
+// ones in green, like git diff --word-diff=color. Without colors they are marked [-removed-]{+added+}
+func WordDiff(before, after string) string {
+	a, b := splitWords(before), splitWords(after)
+	if len(a)*len(b) > maxWordDiff {
+		return markWords(removedWord, "[-", "-]", before) + markWords(addedWord, "{+", "+}", after)
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out, removed, added strings.Builder
+	flush := func() {
+		out.WriteString(markWords(removedWord, "[-", "-]", removed.String()))
+		out.WriteString(markWords(addedWord, "{+", "+}", added.String()))
+		removed.Reset()
+		added.Reset()
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			out.WriteString(a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed.WriteString(a[i])
+			i++
+		default:
+			added.WriteString(b[j])
+			j++
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// splitWords split s into words, runs of whitespace and single punctuation characters
+func splitWords(s string) []string {
+	var words []string
+	kind := func(r rune) int {
+		switch {
+		case unicode.IsSpace(r):
+			return 1
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 2
+		}
+		return 3
+	}
+	start := 0
+	runes := []rune(s)
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || kind(runes[i]) == 3 || kind(runes[i]) != kind(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return words
+}
+
+// markWords color s line by line, so the pager keeps the colors of every line
+func markWords(c *color.Color, open, end, s string) string {
+	if s == "" {
+		return ""
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		if color.NoColor {
+			lines[i] = open + line + end
+		} else {
+			lines[i] = c.Sprint(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
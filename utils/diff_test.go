@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestWordDiff(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	long := strings.Repeat("a ", 1100)
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{"single word", "foo bar baz", "foo qux baz", "foo [-bar-]{+qux+} baz"},
+		{"punctuation", "x := f(a, b)", "x := g(a, c)", "x := [-f-]{+g+}(a, [-b-]{+c+})"},
+		{"added line", "a", "a\nb", "a\n{+b+}"},
+		{"changed line", "a\nb", "a\nc", "a\n[-b-]{+c+}"},
+		{"empty before", "", "new", "{+new+}"},
+		{"empty after", "old", "", "[-old-]"},
+		{"both empty", "", "", ""},
+		{"identical", "same line", "same line", "same line"},
+		{"over the size cap", long, long + "b", "[-" + long + "-]{+" + long + "b+}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordDiff(tt.before, tt.after); got != tt.want {
+				t.Errorf("WordDiff(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// Scissors the lines after it are ignored in the edited text, like git commit
+const Scissors = "# ------------------------ >8 ------------------------"
+
+// EditFile open the file with $EDITOR, default vim
+func EditFile(path string) error {
+	editor, err := shellquote.Split(GetEnv("EDITOR", "vim"))
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// EditText open the text in $EDITOR, and return the saved content without the scissors part
+func EditText(text string) (string, error) {
+	tmp, err := os.CreateTemp("", "lab-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(text)
+	tmp.Close()
+	if err != nil {
+		return "", err
+	}
+	if err = EditFile(tmp.Name()); err != nil {
+		return "", err
+	}
+	buf, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	content, _, _ := strings.Cut(string(buf), Scissors)
+	return content, nil
+}
+
+// ParseTitle return the first line as title, and the rest as description
+func ParseTitle(text string) (title, description string) {
+	title, description, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(description)
+}
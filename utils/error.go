@@ -23,3 +23,8 @@ func PrintErr(err error) {
 		fmt.Println(err.Error())
 	}
 }
+
+// Warn : print the warning message in red, but not exit
+func Warn(msg ...interface{}) {
+	PrintlnWithColor(ColorFg(fmt.Sprint(msg...), "#F08080"))
+}
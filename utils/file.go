@@ -37,3 +37,11 @@ func ReadLines(filePath string) (lines []string, err error) {
 	}
 	return lines, err
 }
+
+// ReadFileOrStdin read the whole file, - means stdin
+func ReadFileOrStdin(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
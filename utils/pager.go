@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// StartPager pipe the output to $PAGER, default less -R. Without a terminal, or if the pager
+// can't start, the output is written to stdout. Call the returned func to wait for the pager
+func StartPager() (io.Writer, func()) {
+	noPager := func() {}
+	if !IsTTY() {
+		return os.Stdout, noPager
+	}
+	pager, err := shellquote.Split(GetEnv("PAGER", "less -R"))
+	if err != nil || len(pager) == 0 {
+		return os.Stdout, noPager
+	}
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noPager
+	}
+	if err = cmd.Start(); err != nil {
+		return os.Stdout, noPager
+	}
+	return in, func() {
+		_ = in.Close()
+		_ = cmd.Wait()
+	}
+}
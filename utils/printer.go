@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Printer print the result of a command
+type Printer interface {
+	Print(v interface{}) error
+}
+
+// TablePrinter print the human readable output of the command with Render, v is ignored
+type TablePrinter struct {
+	Render func()
+}
+
+func (p TablePrinter) Print(_ interface{}) error {
+	p.Render()
+	return nil
+}
+
+// JSONPrinter print v as indented json
+type JSONPrinter struct {
+	Writer io.Writer
+}
+
+func (p JSONPrinter) Print(v interface{}) error {
+	encoder := json.NewEncoder(p.Writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// YAMLPrinter print v as yaml, the keys are the json ones of the gitlab api
+type YAMLPrinter struct {
+	Writer io.Writer
+}
+
+func (p YAMLPrinter) Print(v interface{}) error {
+	// round trip through json, so the json tags and omitempty apply
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err = json.Unmarshal(buf, &data); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(p.Writer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(data); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// NewPrinter return the printer of the output format, render draws the table output
+func NewPrinter(format string, render func()) (Printer, error) {
+	switch format {
+	case "", "table":
+		return TablePrinter{Render: render}, nil
+	case "json":
+		return JSONPrinter{Writer: os.Stdout}, nil
+	case "yaml":
+		return YAMLPrinter{Writer: os.Stdout}, nil
+	}
+	return nil, fmt.Errorf("invalid output %s, use table, json or yaml", format)
+}
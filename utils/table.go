@@ -0,0 +1,12 @@
+package utils
+
+import (
+	"text/tabwriter"
+
+	"github.com/mattn/go-colorable"
+)
+
+// NewTable return a tab separated table writer on stdout, call Flush after writing the rows
+func NewTable() *tabwriter.Writer {
+	return tabwriter.NewWriter(colorable.NewColorableStdout(), 0, 0, 2, ' ', 0)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ParseRelativeTime parse a RFC3339 time, or a time relative to now
+// like 1h, 7d, 2w or 1m (hours, days, weeks, months ago)
+func ParseRelativeTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	invalid := fmt.Errorf("invalid time %q, use RFC3339 or a relative time like 1h, 7d, 2w, 1m", s)
+	// Atoi accepts a sign, like +5d
+	if len(s) < 2 || s[0] < '0' || s[0] > '9' {
+		return time.Time{}, invalid
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	now := time.Now()
+	switch s[len(s)-1] {
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	}
+	return time.Time{}, invalid
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"1h", now.Add(-time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"1m", now.AddDate(0, -1, 0)},
+		{"0d", now},
+		{"2026-10-01T10:00:00Z", time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseRelativeTime(tt.in)
+		if err != nil {
+			t.Errorf("ParseRelativeTime(%q): %v", tt.in, err)
+			continue
+		}
+		if d := got.Sub(tt.want); d < -time.Second || d > time.Second {
+			t.Errorf("ParseRelativeTime(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRelativeTimeInvalid(t *testing.T) {
+	for _, in := range []string{"", "d", "7", "7y", "xd", "+5d", "-5d", " 5d", "1.5h", "2026-10-01"} {
+		if got, err := ParseRelativeTime(in); err == nil {
+			t.Errorf("ParseRelativeTime(%q) = %s, want an error", in, got)
+		}
+	}
+}
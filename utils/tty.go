@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// IsTTY check if the stdout is a terminal, e.g. false in CI or when piped
+func IsTTY() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}